@@ -1,51 +1,80 @@
 package main
 
 import (
+	"context"
+	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 
 	"sample-micro-service-api/apps/backend/app-service/internal/wire"
+	"sample-micro-service-api/package-go/config"
 	"sample-micro-service-api/package-go/logging"
+	"sample-micro-service-api/package-go/tracing"
 )
 
 func main() {
-	// ログの初期化（環境変数から自動設定）
-	if err := logging.InitFromEnv(); err != nil {
-		logging.Fatal("Failed to initialize logger", zap.Error(err))
+	// 設定の読み込み（環境変数 > .env > CONFIG_FILEのYAML > 既定値）
+	// ロガーの設定も含むため、失敗時は標準のlogで不足・不正な項目をまとめて出力する
+	cfg, err := config.Load(config.Options{EnvFiles: []string{".env"}})
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// ログの初期化
+	if err := logging.InitFromConfig(cfg, "app-service", "1.0.0"); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 	defer logging.Sync()
 
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		logging.Warn("Warning: .env file not found", zap.Error(err))
+	// トレースの初期化（OTEL_TRACES_EXPORTER=none の場合もtraceparentは伝搬する）
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, "app-service", "1.0.0")
+	if err != nil {
+		logging.Fatal("Failed to initialize tracing", zap.Error(err))
 	}
 
-	// Initialize app using Wire (データベース、サービス、ハンドラーのみ)
-	server, cleanup, err := wire.InitializeApp()
+	// Initialize app using Wire (設定、データベース、サービス、ハンドラー)
+	server, cleanup, err := wire.InitializeApp(cfg)
 	if err != nil {
 		logging.Fatal("Failed to initialize app", zap.Error(err))
 	}
-	defer cleanup()
 
 	logging.Info("Database connection successful")
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	// SIGTERM（Cloud Runのスケールダウン等）とSIGINTで停止処理を開始
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	go func() {
+		// 停止処理中にもう一度シグナルを受けた場合は即時終了できるよう、シグナルの捕捉を解除する
+		<-ctx.Done()
+		stop()
+	}()
 
-	env := os.Getenv("APP_ENV")
-	if env == "" {
-		env = "development"
+	logging.Info("Starting API server",
+		zap.String("port", cfg.App.Port),
+		zap.String("environment", cfg.App.Environment),
+		zap.Duration("readTimeout", cfg.HTTP.ReadTimeout),
+		zap.Duration("writeTimeout", cfg.HTTP.WriteTimeout),
+		zap.Duration("idleTimeout", cfg.HTTP.IdleTimeout),
+	)
+	runErr := server.Run(ctx)
+
+	// リスナー停止後にDB接続を閉じる（logging.Fatalはdeferを実行しないため明示的に呼ぶ）
+	cleanup()
+	logging.Info("Database connection closed")
+
+	// 未送信のスパンを送り出す
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		logging.Warn("Failed to flush traces", zap.Error(err))
 	}
+	cancel()
 
-	logging.Info("Starting API server", 
-		zap.String("port", port),
-		zap.String("environment", env),
-	)
-	if err := server.Start(":" + port); err != nil {
-		logging.Fatal("Failed to start server", zap.Error(err))
+	if runErr != nil {
+		logging.Fatal("Server stopped with error", zap.Error(runErr))
 	}
-} 
\ No newline at end of file
+	logging.Info("Server shut down gracefully")
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// brotliLevel はレスポンスを都度圧縮するため、圧縮率より速度を優先したbrotliの圧縮レベル
+const brotliLevel = 4
+
+var (
+	gzipWriters = sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	brotliWriters = sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(io.Discard, brotliLevel)
+	}}
+)
+
+// compressor はgzip.Writerとbrotli.Writerの共通のメソッド
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressionMiddleware はレスポンスをbrotli・gzipで圧縮するミドルウェア
+// COMPRESSION_CONTENT_TYPESのContent-Typeで、COMPRESSION_MIN_SIZE以上のレスポンスのみ圧縮する。
+// 先頭のMinSizeバイトまではバッファし、超えた時点（またはFlush時）から圧縮しながら書き出すため、エクスポートのストリーミングにも使える。
+func (s *Server) compressionMiddleware() gin.HandlerFunc {
+	config := s.compression
+	contentTypes := make(map[string]bool, len(config.ContentTypes))
+	for _, contentType := range config.ContentTypes {
+		contentTypes[strings.ToLower(strings.TrimSpace(contentType))] = true
+	}
+
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if !config.Enabled || encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			minSize:        config.MinSize,
+			contentTypes:   contentTypes,
+		}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding はAccept-Encodingから使う圧縮形式（br, gzip）を選ぶ。どちらも受け付けない場合は空文字
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		accepted[name] = true
+	}
+
+	switch {
+	case accepted["br"]:
+		return "br"
+	case accepted["gzip"], accepted["*"]:
+		return "gzip"
+	}
+	return ""
+}
+
+// compressWriter は圧縮するかを決めるまでレスポンスをバッファするResponseWriter
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding     string
+	minSize      int
+	contentTypes map[string]bool
+
+	checked    bool // 圧縮対象かどうかを判定済みか
+	eligible   bool
+	decided    bool
+	buffer     bytes.Buffer
+	compressor compressor // nilの場合は圧縮せずにそのまま書き出す
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		if !w.compressible() {
+			w.decide(false)
+		} else {
+			w.buffer.Write(data)
+			if w.buffer.Len() < w.minSize {
+				return len(data), nil
+			}
+			if err := w.start(true); err != nil {
+				return 0, err
+			}
+			return len(data), nil
+		}
+	}
+
+	if w.compressor != nil {
+		return w.compressor.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush はストリーミング中の出力を送り出す。MinSizeに達する前でも圧縮対象であれば圧縮を始める
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.start(w.compressible())
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// compressible はステータスコード・Content-Type・Content-Encodingから圧縮対象かどうかを判定する（最初の書き込み時に1度だけ判定する）
+func (w *compressWriter) compressible() bool {
+	if !w.checked {
+		w.checked = true
+		w.eligible = w.eligibleResponse()
+	}
+	return w.eligible
+}
+
+func (w *compressWriter) eligibleResponse() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	switch status := w.Status(); {
+	case status < http.StatusOK, status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !w.contentTypes[mediaType] {
+		return false
+	}
+
+	// 圧縮の有無でレスポンスが変わるため、キャッシュに区別させる
+	header.Add("Vary", "Accept-Encoding")
+	return true
+}
+
+// start は圧縮するかを確定し、バッファした内容を書き出す
+func (w *compressWriter) start(compress bool) error {
+	w.decide(compress)
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	data := w.buffer.Bytes()
+	w.buffer = bytes.Buffer{}
+	var err error
+	if w.compressor != nil {
+		_, err = w.compressor.Write(data)
+	} else {
+		_, err = w.ResponseWriter.Write(data)
+	}
+	return err
+}
+
+func (w *compressWriter) decide(compress bool) {
+	w.decided = true
+	if !compress {
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+
+	if w.encoding == "br" {
+		w.compressor = brotliWriters.Get().(*brotli.Writer)
+	} else {
+		w.compressor = gzipWriters.Get().(*gzip.Writer)
+	}
+	w.compressor.Reset(w.ResponseWriter)
+}
+
+// finish はMinSizeに達しなかったレスポンスをそのまま書き出し、圧縮していれば終端を書き出す
+func (w *compressWriter) finish() {
+	if !w.decided {
+		_ = w.start(false)
+	}
+	if w.compressor == nil {
+		return
+	}
+
+	_ = w.compressor.Close()
+	w.compressor.Reset(io.Discard)
+	if w.encoding == "br" {
+		brotliWriters.Put(w.compressor)
+	} else {
+		gzipWriters.Put(w.compressor)
+	}
+	w.compressor = nil
+}
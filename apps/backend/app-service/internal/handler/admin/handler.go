@@ -0,0 +1,98 @@
+package admin_handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/problem"
+	"sample-micro-service-api/package-go/config"
+	"sample-micro-service-api/package-go/logging"
+	appservice "sample-micro-service-api/package-go/response/app-service"
+)
+
+// inheritLevel は名前付きロガーの個別のレベルを解除し、ルートのレベルに従わせる指定
+const inheritLevel = "inherit"
+
+// Handler は運用向けの管理用エンドポイントを処理する
+type Handler struct {
+	token string
+}
+
+func NewHandler(cfg config.AdminConfig) *Handler {
+	return &Handler{
+		token: cfg.Token,
+	}
+}
+
+// RequireToken は Authorization: Bearer <ADMIN_TOKEN> を検証するミドルウェア
+// ADMIN_TOKENが未設定の場合は管理用エンドポイントが存在しないものとして404を返す
+func (h *Handler) RequireToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.token == "" {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			logger(c).Warn("Rejected admin request", zap.String("path", c.Request.URL.Path), zap.String("clientIP", c.ClientIP()))
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, problem.New(c, http.StatusUnauthorized, "Unauthorized", "A valid admin token is required"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetLogLevels - 現在のログレベル取得
+func (h *Handler) GetLogLevels(c *gin.Context) {
+	c.JSON(http.StatusOK, logLevels())
+}
+
+// UpdateLogLevel - ログレベル変更（このインスタンスのみ、再起動で設定値に戻る）
+func (h *Handler) UpdateLogLevel(c *gin.Context) {
+	var req appservice.UpdateLogLevelJSONBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "Invalid request body"))
+		return
+	}
+
+	var name string
+	if req.Logger != nil {
+		name = *req.Logger
+		if !slices.Contains(logging.LoggerNames, name) {
+			c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "logger must be one of "+strings.Join(logging.LoggerNames, ", ")))
+			return
+		}
+	}
+
+	if req.Level == inheritLevel && name != "" {
+		logging.ResetLevel(name)
+	} else if err := logging.SetLevel(name, req.Level); err != nil {
+		c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", err.Error()))
+		return
+	}
+
+	// レベルに関わらず変更を記録する
+	logger(c).Warn("Log level changed", zap.String("targetLogger", name), zap.String("level", req.Level), zap.String("clientIP", c.ClientIP()))
+	c.JSON(http.StatusOK, logLevels())
+}
+
+func logLevels() appservice.ModelLogLevels {
+	root, named := logging.Levels()
+	return appservice.ModelLogLevels{
+		Level:   root,
+		Loggers: named,
+	}
+}
+
+// logger はリクエストのロガー（handlerロガー）を返す
+func logger(c *gin.Context) *zap.Logger {
+	return logging.Named(logging.FromContext(c.Request.Context()), logging.LoggerHandler)
+}
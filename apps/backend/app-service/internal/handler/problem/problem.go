@@ -0,0 +1,37 @@
+package problem
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"sample-micro-service-api/package-go/logging"
+	appservice "sample-micro-service-api/package-go/response/app-service"
+	"sample-micro-service-api/package-go/tracing"
+)
+
+// New はエラーレスポンス（RFC 7807形式）を作成する
+// instanceにはリクエストID（X-Request-ID）、traceIdにはトレースID（トレースがない場合はリクエストID）を入れる
+func New(c *gin.Context, status int, title, detail string) appservice.CommonError {
+	return appservice.CommonError{
+		Status:   int32(status),
+		Title:    title,
+		Detail:   &detail,
+		Instance: requestID(c),
+		TraceId:  traceID(c),
+	}
+}
+
+// traceID はリクエストのトレースIDを返す（トレースがない場合はリクエストID）
+func traceID(c *gin.Context) *string {
+	if id := tracing.TraceID(c.Request.Context()); id != "" {
+		return &id
+	}
+	return requestID(c)
+}
+
+// requestID はリクエストID（X-Request-ID）を返す
+func requestID(c *gin.Context) *string {
+	if id := logging.RequestID(c.Request.Context()); id != "" {
+		return &id
+	}
+	return nil
+}
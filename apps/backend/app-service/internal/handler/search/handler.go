@@ -0,0 +1,87 @@
+package search_handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/problem"
+	search_service "sample-micro-service-api/apps/backend/app-service/internal/service/search"
+	"sample-micro-service-api/package-go/httpcache"
+	"sample-micro-service-api/package-go/logging"
+)
+
+const (
+	defaultLimit = 5
+	maxLimit     = 50
+	// maxQueryLength は検索語の上限文字数（列の最大長に合わせる）
+	maxQueryLength = 255
+)
+
+type Handler struct {
+	searchService search_service.ServiceInterface
+}
+
+func NewHandler(searchService search_service.ServiceInterface) *Handler {
+	return &Handler{
+		searchService: searchService,
+	}
+}
+
+// Search - システム・プロジェクト・グループ・自治体の横断検索
+func (h *Handler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" || utf8.RuneCountInString(query) > maxQueryLength {
+		logger(c).Warn("Invalid q parameter for search", zap.String("q", query))
+		c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "q is required and must be at most 255 characters"))
+		return
+	}
+
+	limit := defaultLimit
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxLimit {
+			logger(c).Warn("Invalid limit parameter for search", zap.String("limit", v))
+			c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "limit must be an integer between 1 and 50"))
+			return
+		}
+		limit = parsed
+	}
+
+	logger(c).Info("Searching across entities",
+		zap.String("q", query),
+		zap.Int("limit", limit),
+	)
+
+	result, err := h.searchService.Search(c.Request.Context(), query, limit)
+	if err != nil {
+		logger(c).Error("Failed to search",
+			zap.Error(err),
+			zap.String("q", query),
+		)
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to search"))
+		return
+	}
+
+	notModified, err := httpcache.WriteJSON(c.Writer, c.Request, result)
+	if err != nil {
+		logger(c).Error("Failed to encode search results", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to search"))
+		return
+	}
+	if notModified {
+		logger(c).Debug("Search results not modified", zap.Int("sections", len(result.Sections)))
+		return
+	}
+
+	logger(c).Info("Successfully searched across entities", zap.Int("sections", len(result.Sections)))
+}
+
+// logger はリクエストのロガー（handlerロガー）を返す
+func logger(c *gin.Context) *zap.Logger {
+	return logging.Named(logging.FromContext(c.Request.Context()), logging.LoggerHandler)
+}
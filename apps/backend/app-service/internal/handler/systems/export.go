@@ -0,0 +1,283 @@
+package systems_handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/problem"
+	appservice "sample-micro-service-api/package-go/response/app-service"
+)
+
+// exportFormat はシステム一覧の出力形式
+type exportFormat string
+
+const (
+	exportFormatJSON   exportFormat = "json"
+	exportFormatCSV    exportFormat = "csv"
+	exportFormatXLSX   exportFormat = "xlsx"
+	exportFormatNDJSON exportFormat = "ndjson"
+)
+
+const (
+	mimeCSV    = "text/csv"
+	mimeXLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	mimeNDJSON = "application/x-ndjson"
+)
+
+// negotiateExportFormat は ?format= を優先し、なければAcceptヘッダーから出力形式を決定
+func negotiateExportFormat(c *gin.Context) (exportFormat, bool) {
+	if format := c.Query("format"); format != "" {
+		switch exportFormat(strings.ToLower(format)) {
+		case exportFormatJSON:
+			return exportFormatJSON, true
+		case exportFormatCSV:
+			return exportFormatCSV, true
+		case exportFormatXLSX:
+			return exportFormatXLSX, true
+		case exportFormatNDJSON:
+			return exportFormatNDJSON, true
+		default:
+			return "", false
+		}
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, mimeCSV, mimeXLSX, mimeNDJSON) {
+	case mimeCSV:
+		return exportFormatCSV, true
+	case mimeXLSX:
+		return exportFormatXLSX, true
+	case mimeNDJSON:
+		return exportFormatNDJSON, true
+	default:
+		return exportFormatJSON, true
+	}
+}
+
+// exportColumn はCSV/XLSXの1列分の定義
+type exportColumn struct {
+	name   string // 英語ヘッダー（JSONのフィールド名）
+	nameJa string // 日本語ヘッダー（取り込みAPIのヘッダーとしてもそのまま使える）
+	value  func(system appservice.ModelSystem) string
+}
+
+var exportColumns = []exportColumn{
+	{"id", "ID", func(s appservice.ModelSystem) string { return s.Id.String() }},
+	{"systemName", "システム名", func(s appservice.ModelSystem) string { return s.SystemName }},
+	{"systemNameKana", "システム名カナ", func(s appservice.ModelSystem) string { return derefString(s.SystemNameKana) }},
+	{"localGovernmentId", "自治体コード", func(s appservice.ModelSystem) string { return derefString(s.LocalGovernmentId) }},
+	{"mailAddress", "メールアドレス", func(s appservice.ModelSystem) string { return string(s.MailAddress) }},
+	{"telephone", "電話番号", func(s appservice.ModelSystem) string { return derefString(s.Telephone) }},
+	{"remark", "備考", func(s appservice.ModelSystem) string { return derefString(s.Remark) }},
+	{"createdAt", "作成日時", func(s appservice.ModelSystem) string { return s.CreatedAt.Format(time.RFC3339) }},
+	{"updatedAt", "更新日時", func(s appservice.ModelSystem) string { return s.UpdatedAt.Format(time.RFC3339) }},
+}
+
+// exportHeaders はヘッダー行を返す（japaneseがtrueなら日本語ヘッダー）
+func exportHeaders(japanese bool) []string {
+	headers := make([]string, len(exportColumns))
+	for i, col := range exportColumns {
+		if japanese {
+			headers[i] = col.nameJa
+		} else {
+			headers[i] = col.name
+		}
+	}
+	return headers
+}
+
+func exportValues(system appservice.ModelSystem) []string {
+	values := make([]string, len(exportColumns))
+	for i, col := range exportColumns {
+		values[i] = col.value(system)
+	}
+	return values
+}
+
+// systemExportWriter は1行ずつ書き出すエクスポートライター
+type systemExportWriter interface {
+	WriteSystem(system appservice.ModelSystem) error
+	Close() error
+}
+
+// csvExportWriter はExcelで文字化けしないようBOM付きUTF-8でCSVを書き出す
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer, japanese bool) (*csvExportWriter, error) {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportHeaders(japanese)); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{writer: writer}, nil
+}
+
+func (w *csvExportWriter) WriteSystem(system appservice.ModelSystem) error {
+	return w.writer.Write(exportValues(system))
+}
+
+func (w *csvExportWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// ndjsonExportWriter は1行1JSONで書き出す
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func newNDJSONExportWriter(w io.Writer) *ndjsonExportWriter {
+	return &ndjsonExportWriter{encoder: json.NewEncoder(w)}
+}
+
+func (w *ndjsonExportWriter) WriteSystem(system appservice.ModelSystem) error {
+	return w.encoder.Encode(system)
+}
+
+func (w *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// xlsxExportWriter はexcelizeのStreamWriterで書き出す
+// XLSXはZIP形式のため送信はClose時にまとめて行うが、行データは一定量を超えると一時ファイルに退避されメモリは増えない
+type xlsxExportWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXExportWriter(w io.Writer, japanese bool) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	writer := &xlsxExportWriter{out: w, file: file, stream: stream}
+	if err := writer.writeRow(exportHeaders(japanese)); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *xlsxExportWriter) writeRow(values []string) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	return w.stream.SetRow(cell, row)
+}
+
+func (w *xlsxExportWriter) WriteSystem(system appservice.ModelSystem) error {
+	return w.writeRow(exportValues(system))
+}
+
+func (w *xlsxExportWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.Write(w.out)
+}
+
+// exportSystems - システム一覧をCSV/XLSX/NDJSONでストリーミング出力
+func (h *Handler) exportSystems(c *gin.Context, format exportFormat, systemName, email, localGovernmentId string) {
+	var japanese bool
+	switch c.DefaultQuery("headers", "en") {
+	case "en":
+	case "ja":
+		japanese = true
+	default:
+		c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "headers must be \"en\" or \"ja\""))
+		return
+	}
+
+	logger(c).Info("Exporting systems",
+		zap.String("format", string(format)),
+		zap.String("systemName", systemName),
+		zap.String("email", email),
+		zap.String("localGovernmentId", localGovernmentId),
+	)
+
+	cursor, err := h.systemsService.OpenSystemCursor(c.Request.Context(), systemName, email, localGovernmentId)
+	if err != nil {
+		logger(c).Error("Failed to export systems", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve systems"))
+		return
+	}
+	defer cursor.Close()
+
+	filename := fmt.Sprintf("systems_%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	var writer systemExportWriter
+	switch format {
+	case exportFormatCSV:
+		c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+		c.Status(http.StatusOK)
+		writer, err = newCSVExportWriter(c.Writer, japanese)
+	case exportFormatXLSX:
+		c.Header("Content-Type", mimeXLSX)
+		c.Status(http.StatusOK)
+		writer, err = newXLSXExportWriter(c.Writer, japanese)
+	default:
+		c.Header("Content-Type", mimeNDJSON)
+		c.Status(http.StatusOK)
+		writer = newNDJSONExportWriter(c.Writer)
+	}
+	if err != nil {
+		logger(c).Error("Failed to start system export", zap.String("format", string(format)), zap.Error(err))
+		return
+	}
+
+	// ヘッダー送信後はエラーレスポンスを返せないため、途中のエラーはログのみ
+	count := 0
+	for cursor.Next() {
+		if err := writer.WriteSystem(cursor.System()); err != nil {
+			logger(c).Error("Failed to write exported system", zap.Int("count", count), zap.Error(err))
+			return
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		logger(c).Error("Failed to read systems during export", zap.Int("count", count), zap.Error(err))
+		return
+	}
+	if err := writer.Close(); err != nil {
+		logger(c).Error("Failed to finish system export", zap.Int("count", count), zap.Error(err))
+		return
+	}
+
+	logger(c).Info("Successfully exported systems",
+		zap.String("format", string(format)),
+		zap.Int("count", count),
+	)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/problem"
 	systems_service "sample-micro-service-api/apps/backend/app-service/internal/service/systems"
+	"sample-micro-service-api/package-go/httpcache"
 	"sample-micro-service-api/package-go/logging"
 	appservice "sample-micro-service-api/package-go/response/app-service"
 )
@@ -24,66 +26,92 @@ func NewHandler(systemsService systems_service.ServiceInterface) *Handler {
 // GetSystems - システム一覧取得
 func (h *Handler) GetSystems(c *gin.Context) {
 	// クエリパラメータを取得
+	query := c.Query("q")
 	systemName := c.Query("systemName")
 	email := c.Query("email")
 	localGovernmentId := c.Query("localGovernmentId")
 
+	// CSV/XLSX/NDJSONが要求された場合はストリーミング出力
+	format, ok := negotiateExportFormat(c)
+	if !ok {
+		logger(c).Warn("Unsupported export format", zap.String("format", c.Query("format")))
+		c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "format must be one of json, csv, xlsx, ndjson"))
+		return
+	}
+	if format != exportFormatJSON {
+		if query != "" {
+			c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "q cannot be combined with csv, xlsx or ndjson export; use systemName instead"))
+			return
+		}
+		h.exportSystems(c, format, systemName, email, localGovernmentId)
+		return
+	}
+
+	// あいまい検索は類似度順で返す
+	if query != "" {
+		h.fuzzySearchSystems(c, query, email, localGovernmentId)
+		return
+	}
+
 	var systems []appservice.ModelSystem
 	var err error
 
 	// 検索パラメータが指定されている場合は検索を実行、そうでなければ全件取得
 	if systemName != "" || email != "" || localGovernmentId != "" {
-		logging.Info("Searching systems with parameters",
+		logger(c).Info("Searching systems with parameters",
 			zap.String("systemName", systemName),
 			zap.String("email", email),
 			zap.String("localGovernmentId", localGovernmentId),
 		)
 		systems, err = h.systemsService.SearchSystems(c.Request.Context(), systemName, email, localGovernmentId)
 	} else {
-		logging.Debug("Getting all systems")
+		logger(c).Debug("Getting all systems")
 		systems, err = h.systemsService.GetSystems(c.Request.Context())
 	}
 
 	if err != nil {
-		logging.Error("Failed to retrieve systems",
+		logger(c).Error("Failed to retrieve systems",
 			zap.Error(err),
 			zap.String("systemName", systemName),
 			zap.String("email", email),
 			zap.String("localGovernmentId", localGovernmentId),
 		)
-		c.JSON(http.StatusInternalServerError, appservice.CommonError{
-			Status: http.StatusInternalServerError,
-			Title:  "Internal Server Error",
-			Detail: stringPtr("Failed to retrieve systems"),
-		})
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve systems"))
 		return
 	}
 
-	logging.Info("Successfully retrieved systems", zap.Int("count", len(systems)))
-	c.JSON(http.StatusOK, systems)
+	// 一覧の内容が変わっていなければ（If-None-MatchがETagと一致すれば）304を返す
+	notModified, err := httpcache.WriteJSON(c.Writer, c.Request, systems)
+	if err != nil {
+		logger(c).Error("Failed to encode systems", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve systems"))
+		return
+	}
+	if notModified {
+		logger(c).Debug("Systems not modified", zap.Int("count", len(systems)))
+		return
+	}
+
+	logger(c).Info("Successfully retrieved systems", zap.Int("count", len(systems)))
 }
 
 // GetSystemById - システム詳細取得
 func (h *Handler) GetSystemById(c *gin.Context) {
 	idParam := c.Param("id")
 	
-	logging.Debug("Getting system by ID", zap.String("id", idParam))
+	logger(c).Debug("Getting system by ID", zap.String("id", idParam))
 	
 	system, err := h.systemsService.GetSystemById(c.Request.Context(), idParam)
 	if err != nil {
-		logging.Warn("System not found",
+		logger(c).Warn("System not found",
 			zap.String("id", idParam),
 			zap.Error(err),
 		)
-		c.JSON(http.StatusNotFound, appservice.CommonError{
-			Status: http.StatusNotFound,
-			Title:  "Not Found",
-			Detail: stringPtr("System not found"),
-		})
+		c.JSON(http.StatusNotFound, problem.New(c, http.StatusNotFound, "Not Found", "System not found"))
 		return
 	}
 
-	logging.Info("Successfully retrieved system", zap.String("id", idParam))
+	logger(c).Info("Successfully retrieved system", zap.String("id", idParam))
 	c.JSON(http.StatusOK, system)
 }
 
@@ -91,32 +119,24 @@ func (h *Handler) GetSystemById(c *gin.Context) {
 func (h *Handler) CreateSystem(c *gin.Context) {
 	var req appservice.CreateSystemJSONBody
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logging.Warn("Invalid request body for system creation", zap.Error(err))
-		c.JSON(http.StatusBadRequest, appservice.CommonError{
-			Status: http.StatusBadRequest,
-			Title:  "Bad Request",
-			Detail: stringPtr("Invalid request body"),
-		})
+		logger(c).Warn("Invalid request body for system creation", zap.Error(err))
+		c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "Invalid request body"))
 		return
 	}
 
-	logging.Info("Creating new system", zap.String("systemName", req.SystemName))
+	logger(c).Info("Creating new system", zap.String("systemName", req.SystemName))
 
 	system, err := h.systemsService.CreateSystem(c.Request.Context(), req)
 	if err != nil {
-		logging.Error("Failed to create system",
+		logger(c).Error("Failed to create system",
 			zap.Error(err),
 			zap.String("systemName", req.SystemName),
 		)
-		c.JSON(http.StatusInternalServerError, appservice.CommonError{
-			Status: http.StatusInternalServerError,
-			Title:  "Internal Server Error",
-			Detail: stringPtr("Failed to create system"),
-		})
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to create system"))
 		return
 	}
 
-	logging.Info("Successfully created system", 
+	logger(c).Info("Successfully created system", 
 		zap.String("id", system.Id.String()),
 		zap.String("systemName", req.SystemName),
 	)
@@ -129,38 +149,30 @@ func (h *Handler) UpdateSystem(c *gin.Context) {
 	
 	var req appservice.UpdateSystemJSONBody
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logging.Warn("Invalid request body for system update", 
+		logger(c).Warn("Invalid request body for system update", 
 			zap.String("id", idParam),
 			zap.Error(err),
 		)
-		c.JSON(http.StatusBadRequest, appservice.CommonError{
-			Status: http.StatusBadRequest,
-			Title:  "Bad Request",
-			Detail: stringPtr("Invalid request body"),
-		})
+		c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "Invalid request body"))
 		return
 	}
 
-	logging.Info("Updating system", 
+	logger(c).Info("Updating system", 
 		zap.String("id", idParam),
 		zap.String("systemName", req.SystemName),
 	)
 
 	system, err := h.systemsService.UpdateSystem(c.Request.Context(), idParam, req)
 	if err != nil {
-		logging.Error("Failed to update system",
+		logger(c).Error("Failed to update system",
 			zap.String("id", idParam),
 			zap.Error(err),
 		)
-		c.JSON(http.StatusNotFound, appservice.CommonError{
-			Status: http.StatusNotFound,
-			Title:  "Not Found",
-			Detail: stringPtr("System not found or failed to update"),
-		})
+		c.JSON(http.StatusNotFound, problem.New(c, http.StatusNotFound, "Not Found", "System not found or failed to update"))
 		return
 	}
 
-	logging.Info("Successfully updated system", zap.String("id", idParam))
+	logger(c).Info("Successfully updated system", zap.String("id", idParam))
 	c.JSON(http.StatusOK, system)
 }
 
@@ -168,27 +180,24 @@ func (h *Handler) UpdateSystem(c *gin.Context) {
 func (h *Handler) DeleteSystem(c *gin.Context) {
 	idParam := c.Param("id")
 	
-	logging.Info("Deleting system", zap.String("id", idParam))
+	logger(c).Info("Deleting system", zap.String("id", idParam))
 	
 	err := h.systemsService.DeleteSystem(c.Request.Context(), idParam)
 	if err != nil {
-		logging.Error("Failed to delete system",
+		logger(c).Error("Failed to delete system",
 			zap.String("id", idParam),
 			zap.Error(err),
 		)
-		c.JSON(http.StatusNotFound, appservice.CommonError{
-			Status: http.StatusNotFound,
-			Title:  "Not Found",
-			Detail: stringPtr("System not found"),
-		})
+		c.JSON(http.StatusNotFound, problem.New(c, http.StatusNotFound, "Not Found", "System not found"))
 		return
 	}
 
-	logging.Info("Successfully deleted system", zap.String("id", idParam))
+	logger(c).Info("Successfully deleted system", zap.String("id", idParam))
 	c.Status(http.StatusNoContent)
 }
 
 // ヘルパー関数
-func stringPtr(s string) *string {
-	return &s
-} 
\ No newline at end of file
+// logger はリクエストのロガー（handlerロガー）を返す
+func logger(c *gin.Context) *zap.Logger {
+	return logging.Named(logging.FromContext(c.Request.Context()), logging.LoggerHandler)
+}
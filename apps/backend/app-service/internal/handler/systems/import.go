@@ -0,0 +1,76 @@
+package systems_handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/problem"
+	systems_service "sample-micro-service-api/apps/backend/app-service/internal/service/systems"
+)
+
+// ImportSystems - CSV/XLSXファイルからのシステム一括取り込み
+func (h *Handler) ImportSystems(c *gin.Context) {
+	dryRun := true
+	if v := c.Query("dryRun"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			logger(c).Warn("Invalid dryRun parameter for system import", zap.String("dryRun", v))
+			c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "dryRun must be a boolean"))
+			return
+		}
+		dryRun = parsed
+	}
+	encoding := c.DefaultQuery("encoding", "auto")
+
+	// ボディの上限はHTTP_ROUTE_MAX_BODY_BYTES（既定10MB）で制限される
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			logger(c).Warn("Import file too large", zap.Int64("limit", maxBytesErr.Limit))
+			c.JSON(http.StatusRequestEntityTooLarge, problem.New(c, http.StatusRequestEntityTooLarge, "Payload Too Large", fmt.Sprintf("Request body must not exceed %d bytes", maxBytesErr.Limit)))
+			return
+		}
+		logger(c).Warn("Invalid multipart body for system import", zap.Error(err))
+		c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "A CSV or XLSX file is required in the \"file\" field"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger(c).Error("Failed to open uploaded import file", zap.String("filename", fileHeader.Filename), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to read uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	logger(c).Info("Importing systems",
+		zap.String("filename", fileHeader.Filename),
+		zap.Int64("size", fileHeader.Size),
+		zap.Bool("dryRun", dryRun),
+	)
+
+	result, err := h.systemsService.ImportSystems(c.Request.Context(), file, fileHeader.Filename, encoding, dryRun)
+	if err != nil {
+		if errors.Is(err, systems_service.ErrInvalidImportFile) {
+			c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", err.Error()))
+			return
+		}
+		logger(c).Error("Failed to import systems", zap.String("filename", fileHeader.Filename), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to import systems"))
+		return
+	}
+
+	// コミット指定でもエラー行があれば何も反映せず、結果のみを422で返す
+	if !result.DryRun && !result.Committed {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,61 @@
+package systems_handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/problem"
+	"sample-micro-service-api/package-go/httpcache"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// fuzzySearchSystems - ?q= によるあいまい検索（類似度順）
+func (h *Handler) fuzzySearchSystems(c *gin.Context, query, email, localGovernmentId string) {
+	limit := defaultSearchLimit
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxSearchLimit {
+			logger(c).Warn("Invalid limit parameter for system search", zap.String("limit", v))
+			c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", "limit must be an integer between 1 and 200"))
+			return
+		}
+		limit = parsed
+	}
+
+	logger(c).Info("Fuzzy searching systems",
+		zap.String("q", query),
+		zap.String("email", email),
+		zap.String("localGovernmentId", localGovernmentId),
+		zap.Int("limit", limit),
+	)
+
+	systems, err := h.systemsService.FuzzySearchSystems(c.Request.Context(), query, email, localGovernmentId, limit)
+	if err != nil {
+		logger(c).Error("Failed to search systems",
+			zap.Error(err),
+			zap.String("q", query),
+		)
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve systems"))
+		return
+	}
+
+	notModified, err := httpcache.WriteJSON(c.Writer, c.Request, systems)
+	if err != nil {
+		logger(c).Error("Failed to encode systems", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve systems"))
+		return
+	}
+	if notModified {
+		logger(c).Debug("Systems not modified", zap.Int("count", len(systems)))
+		return
+	}
+
+	logger(c).Info("Successfully searched systems", zap.Int("count", len(systems)))
+}
@@ -0,0 +1,67 @@
+package vendors_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/problem"
+	vendors_service "sample-micro-service-api/apps/backend/app-service/internal/service/vendors"
+	"sample-micro-service-api/package-go/corporatenumber"
+	"sample-micro-service-api/package-go/httpcache"
+	"sample-micro-service-api/package-go/logging"
+)
+
+type Handler struct {
+	vendorsService vendors_service.ServiceInterface
+}
+
+func NewHandler(vendorsService vendors_service.ServiceInterface) *Handler {
+	return &Handler{
+		vendorsService: vendorsService,
+	}
+}
+
+// GetVendors - ベンダー一覧取得
+func (h *Handler) GetVendors(c *gin.Context) {
+	var corporateNumber string
+	if v := c.Query("corporateNumber"); v != "" {
+		corporateNumber = corporatenumber.Normalize(v)
+		if err := corporatenumber.Validate(corporateNumber); err != nil {
+			logger(c).Warn("Invalid corporateNumber parameter", zap.String("corporateNumber", v), zap.Error(err))
+			c.JSON(http.StatusBadRequest, problem.New(c, http.StatusBadRequest, "Bad Request", err.Error()))
+			return
+		}
+	}
+
+	logger(c).Info("Getting vendors", zap.String("corporateNumber", corporateNumber))
+
+	vendors, err := h.vendorsService.GetVendors(c.Request.Context(), corporateNumber)
+	if err != nil {
+		logger(c).Error("Failed to retrieve vendors",
+			zap.Error(err),
+			zap.String("corporateNumber", corporateNumber),
+		)
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve vendors"))
+		return
+	}
+
+	notModified, err := httpcache.WriteJSON(c.Writer, c.Request, vendors)
+	if err != nil {
+		logger(c).Error("Failed to encode vendors", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, problem.New(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve vendors"))
+		return
+	}
+	if notModified {
+		logger(c).Debug("Vendors not modified", zap.Int("count", len(vendors)))
+		return
+	}
+
+	logger(c).Info("Successfully retrieved vendors", zap.Int("count", len(vendors)))
+}
+
+// logger はリクエストのロガー（handlerロガー）を返す
+func logger(c *gin.Context) *zap.Logger {
+	return logging.Named(logging.FromContext(c.Request.Context()), logging.LoggerHandler)
+}
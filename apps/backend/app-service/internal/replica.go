@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"sample-micro-service-api/package-go/database"
+)
+
+// primaryReadCookie は直前に書き込んだクライアントに付けるCookie（有効な間は読み込みをプライマリに固定する）
+const primaryReadCookie = "db_primary"
+
+// readYourWritesMiddleware は書き込みのリクエストと、その直後のリクエストの読み込みをプライマリに固定するミドルウェア
+// レプリカは遅延があるため、作成・更新した直後の一覧・詳細の取得で自分の変更が見えなくなるのを防ぐ。
+// 書き込みのリクエスト（GET・HEAD・OPTIONS以外）にDB_REPLICA_STICKY_WINDOWの間有効なCookieを付ける（0の場合は付けない）。
+func (s *Server) readYourWritesMiddleware() gin.HandlerFunc {
+	window := s.readYourWrites
+	maxAge := int(math.Ceil(window.Seconds()))
+
+	return func(c *gin.Context) {
+		write := c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead && c.Request.Method != http.MethodOptions
+		_, err := c.Cookie(primaryReadCookie)
+		if write || err == nil {
+			c.Request = c.Request.WithContext(database.WithPrimary(c.Request.Context()))
+		}
+
+		if write && maxAge > 0 {
+			// 結果に関わらず、レスポンスを書き出す前に付ける（失敗した書き込みでも数秒プライマリで読むだけで害はない）
+			c.SetSameSite(http.SameSiteLaxMode)
+			c.SetCookie(primaryReadCookie, "1", maxAge, "/", "", s.appConfig.IsProduction(), true)
+		}
+
+		c.Next()
+	}
+}
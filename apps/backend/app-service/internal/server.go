@@ -1,37 +1,79 @@
 package internal
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
-	"os"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 
+	adminHandler "sample-micro-service-api/apps/backend/app-service/internal/handler/admin"
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/problem"
+	searchHandler "sample-micro-service-api/apps/backend/app-service/internal/handler/search"
 	systemsHandler "sample-micro-service-api/apps/backend/app-service/internal/handler/systems"
+	vendorsHandler "sample-micro-service-api/apps/backend/app-service/internal/handler/vendors"
+	"sample-micro-service-api/package-go/config"
 	"sample-micro-service-api/package-go/database"
+	"sample-micro-service-api/package-go/health"
 	"sample-micro-service-api/package-go/logging"
+	"sample-micro-service-api/package-go/metrics"
+	"sample-micro-service-api/package-go/ratelimit"
+	appservice "sample-micro-service-api/package-go/response/app-service"
 )
 
 type Server struct {
-	dbClient       *database.Client
-	router         *gin.Engine
-	systemsHandler *systemsHandler.Handler
+	appConfig       config.AppConfig
+	httpConfig      config.HTTPConfig
+	corsConfig      config.CORSConfig
+	compression     config.CompressionConfig
+	dbClient        *database.Client
+	healthRegistry  *health.Registry
+	metricsRegistry *prometheus.Registry
+	httpMetrics     *metrics.HTTPMetrics
+	rateLimiter     *ratelimit.Limiter
+	bodyLimits      map[string]int64
+	readYourWrites  time.Duration // 書き込み後に読み込みをプライマリに固定する時間
+	router          *gin.Engine
+	systemsHandler  *systemsHandler.Handler
+	searchHandler   *searchHandler.Handler
+	vendorsHandler  *vendorsHandler.Handler
+	adminHandler    *adminHandler.Handler
+
+	// draining はシャットダウン中（新規リクエストの受付停止を周知中）かどうか
+	draining atomic.Bool
 }
 
-func NewServer(dbClient *database.Client, systemsHandler *systemsHandler.Handler) *Server {
-	// Set Gin mode from environment
-	ginMode := os.Getenv("GIN_MODE")
-	if ginMode == "" {
-		ginMode = gin.ReleaseMode
-	}
-	gin.SetMode(ginMode)
+func NewServer(appConfig config.AppConfig, httpConfig config.HTTPConfig, corsConfig config.CORSConfig, compressionConfig config.CompressionConfig, dbConfig config.DatabaseConfig, dbClient *database.Client, healthRegistry *health.Registry, metricsRegistry *prometheus.Registry, httpMetrics *metrics.HTTPMetrics, rateLimiter *ratelimit.Limiter, systemsHandler *systemsHandler.Handler, searchHandler *searchHandler.Handler, vendorsHandler *vendorsHandler.Handler, adminHandler *adminHandler.Handler) *Server {
+	gin.SetMode(appConfig.GinMode)
 
 	server := &Server{
-		dbClient:       dbClient,
-		router:         gin.New(),
-		systemsHandler: systemsHandler,
+		appConfig:       appConfig,
+		httpConfig:      httpConfig,
+		corsConfig:      corsConfig,
+		compression:     compressionConfig,
+		dbClient:        dbClient,
+		healthRegistry:  healthRegistry,
+		metricsRegistry: metricsRegistry,
+		httpMetrics:     httpMetrics,
+		rateLimiter:     rateLimiter,
+		bodyLimits:      httpConfig.BodyLimits(),
+		readYourWrites:  dbConfig.ReplicaStickyWindow,
+		router:          newRouter(httpConfig),
+		systemsHandler:  systemsHandler,
+		searchHandler:   searchHandler,
+		vendorsHandler:  vendorsHandler,
+		adminHandler:    adminHandler,
 	}
 
 	server.setupMiddleware()
@@ -40,7 +82,46 @@ func NewServer(dbClient *database.Client, systemsHandler *systemsHandler.Handler
 	return server
 }
 
+// trustedPlatforms はHTTP_TRUSTED_PLATFORMの値と、クライアントのIPアドレスを付けるヘッダー
+var trustedPlatforms = map[string]string{
+	"google-app-engine": gin.PlatformGoogleAppEngine,
+	"cloudflare":        gin.PlatformCloudflare,
+}
+
+// newRouter はクライアントのIPアドレス（c.ClientIP）の判定を設定したルーターを作成する
+// 信頼するプロキシが未指定の場合はX-Forwarded-Forなどを使わず、接続元のアドレスをそのまま使う（偽装したヘッダーでレート制限を回避させないため）
+func newRouter(httpConfig config.HTTPConfig) *gin.Engine {
+	router := gin.New()
+	if err := router.SetTrustedProxies(httpConfig.TrustedProxies); err != nil {
+		logging.Warn("Invalid trusted proxies, trusting none", zap.Strings("trustedProxies", httpConfig.TrustedProxies), zap.Error(err))
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.TrustedPlatform = trustedPlatforms[httpConfig.TrustedPlatform]
+	return router
+}
+
+// untracedPaths はトレースを記録しないパス（ヘルスチェック・メトリクスの定期的な取得）
+var untracedPaths = map[string]bool{
+	"/livez":   true,
+	"/readyz":  true,
+	"/health":  true,
+	"/metrics": true,
+}
+
 func (s *Server) setupMiddleware() {
+	// OpenTelemetry tracing middleware（traceparentを引き継ぎ、ルート定義をスパン名にする）
+	s.router.Use(otelgin.Middleware("app-service",
+		otelgin.WithFilter(func(r *http.Request) bool {
+			return !untracedPaths[r.URL.Path]
+		}),
+	))
+
+	// Prometheus metrics middleware
+	s.router.Use(s.metricsMiddleware())
+
+	// Request ID middleware（以降のログにリクエストIDを付ける）
+	s.router.Use(s.requestIDMiddleware())
+
 	// Zap Logger middleware
 	s.router.Use(s.zapLoggerMiddleware())
 
@@ -48,14 +129,80 @@ func (s *Server) setupMiddleware() {
 	s.router.Use(s.zapRecoveryMiddleware())
 
 	// CORS middleware
-	s.router.Use(cors.New(cors.Config{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Content-Type", "Authorization"},
-	}))
+	s.router.Use(cors.New(s.corsOptions()))
+
+	// Response compression middleware (brotli / gzip)
+	s.router.Use(s.compressionMiddleware())
+
+	// Request body size limit middleware
+	s.router.Use(s.bodyLimitMiddleware())
+
+	// Read-your-writes middleware（レプリカがある場合のみ、書き込み直後の読み込みをプライマリに固定する）
+	if len(s.dbClient.ReplicaPools()) > 0 {
+		s.router.Use(s.readYourWritesMiddleware())
+	}
+}
+
+// corsOptions はCORS_*の設定からCORSミドルウェアの設定を作る
+// "*" はすべてのオリジンを許可し（本番環境では設定の読み込み時にエラーになる）、"https://*.example.jp" はサブドメインに一致する
+func (s *Server) corsOptions() cors.Config {
+	config := s.corsConfig
+	options := cors.Config{
+		AllowMethods:     config.AllowMethods,
+		AllowHeaders:     config.AllowHeaders,
+		ExposeHeaders:    config.ExposeHeaders,
+		AllowCredentials: config.AllowCredentials,
+		MaxAge:           config.MaxAge,
+	}
+	if config.AllowAllOrigins() {
+		options.AllowAllOrigins = true
+	} else {
+		options.AllowOrigins = config.AllowOrigins
+		options.AllowWildcard = true
+	}
+	return options
+}
+
+// requestIDHeader はリクエストIDを受け渡すヘッダー
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength は受け入れるリクエストIDの最大長
+const maxRequestIDLength = 128
+
+// requestIDMiddleware はX-Request-IDを引き継ぐ（なければ生成する）ミドルウェア
+// リクエストIDはレスポンスヘッダーに返し、ctxのロガー（logging.FromContext）に設定する
+func (s *Server) requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(requestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// validRequestID はログやヘッダーに出力しても安全なリクエストIDかどうか（英数字と "-_.:" のみ）
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 // zapLoggerMiddleware はzapを使用したGinロガーミドルウェア
+// Cloud Loggingの httpRequest フィールドを出力し、ステータスコードに応じた重要度で記録する
 func (s *Server) zapLoggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -65,49 +212,144 @@ func (s *Server) zapLoggerMiddleware() gin.HandlerFunc {
 		// リクエスト処理
 		c.Next()
 
-		// レスポンス時間とログ出力
-		latency := time.Since(start)
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-		statusCode := c.Writer.Status()
-		bodySize := c.Writer.Size()
-
 		if raw != "" {
 			path = path + "?" + raw
 		}
 
 		// Cloud Logging標準のhttpRequestフィールドを使用
 		httpReq := logging.HttpRequest{
-			RequestMethod: method,
+			RequestMethod: c.Request.Method,
 			RequestUrl:    path,
-			Status:        statusCode,
-			ResponseSize:  string(rune(bodySize)),
-			RemoteIp:      clientIP,
-			Latency:       latency.String(),
+			RequestSize:   c.Request.ContentLength,
+			Status:        c.Writer.Status(),
+			ResponseSize:  int64(c.Writer.Size()),
+			RemoteIp:      c.ClientIP(),
+			ServerIp:      serverIP(c.Request),
+			Latency:       time.Since(start),
 			Protocol:      c.Request.Proto,
 			UserAgent:     c.Request.UserAgent(),
 			Referer:       c.Request.Referer(),
 		}
 
-		// ログレベルをステータスコードに基づいて決定
-		if statusCode >= 500 {
-			logging.LogHttpRequest("HTTP Request", httpReq, 
-				zap.String("level", "ERROR"),
+		logging.LogHttpRequest(c.Request.Context(), "HTTP Request", httpReq)
+	}
+}
+
+// serverIP はリクエストを受け付けたサーバーのIPアドレス（取得できない場合は空文字）
+func serverIP(r *http.Request) string {
+	addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// metricsMiddleware はリクエスト数・レイテンシ・処理中のリクエスト数を記録するミドルウェア
+// ラベルにはルート定義（/api/v1/systems/:id）を使い、どのルートにも一致しない場合は "unmatched" とする
+func (s *Server) metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		done := s.httpMetrics.Start()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		done(c.Request.Method, route, c.Writer.Status())
+	}
+}
+
+// bodyLimitMiddleware はリクエストボディをHTTP_MAX_BODY_BYTES（ルート別の上限があればその値）までに制限するミドルウェア
+// Content-Lengthで上限を超えると分かる場合は413を返し、それ以外は読み込み時に上限で打ち切る
+func (s *Server) bodyLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := s.httpConfig.MaxBodyBytes
+		if routeLimit, ok := s.bodyLimits[c.Request.Method+" "+c.FullPath()]; ok {
+			limit = routeLimit
+		}
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			logging.FromContext(c.Request.Context()).Warn("Request body too large",
+				zap.String("route", c.FullPath()),
+				zap.Int64("contentLength", c.Request.ContentLength),
+				zap.Int64("limit", limit),
 			)
-		} else if statusCode >= 400 {
-			logging.LogHttpRequest("HTTP Request", httpReq,
-				zap.String("level", "WARNING"),
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, problem.New(c, http.StatusRequestEntityTooLarge, "Payload Too Large", fmt.Sprintf("Request body must not exceed %d bytes", limit)))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// rateLimitMiddleware はルートのポリシーに従ってクライアントごとにレート制限するミドルウェア
+// RateLimit-* ヘッダーで残り回数を返し、上限を超えた場合は429とRetry-Afterを返す。
+// ストアのエラー時はリクエストを拒否せずに通す。
+func (s *Server) rateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, ok := s.rateLimiter.Policy(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		result, err := s.rateLimiter.Take(c.Request.Context(), rateLimitKey(c), policy)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("Rate limiter failed, allowing request", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Period)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			s.httpMetrics.RateLimited(c.Request.Method, c.FullPath())
+			logging.FromContext(c.Request.Context()).Warn("Rate limit exceeded",
+				zap.String("policy", policy.Name),
+				zap.String("clientIP", c.ClientIP()),
+				zap.Int("retryAfter", retryAfter),
 			)
-		} else {
-			logging.LogHttpRequest("HTTP Request", httpReq)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, problem.New(c, http.StatusTooManyRequests, "Too Many Requests", fmt.Sprintf("Rate limit of %d requests per %s exceeded; retry after %d seconds", policy.Limit, policy.Period, retryAfter)))
+			return
 		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey はレート制限のキー（認証済みのユーザー、未認証の場合はクライアントのIPアドレス）
+// クライアントのIPアドレスは信頼するプロキシ・プラットフォームの設定（newRouter）に従って判定したもの
+func rateLimitKey(c *gin.Context) string {
+	if subject := ratelimit.Subject(c.Request.Context()); subject != "" {
+		return "user:" + subject
 	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds はヘッダーに返す秒数（切り上げ）
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
 
 // zapRecoveryMiddleware はzapを使用したGinリカバリーミドルウェア
 func (s *Server) zapRecoveryMiddleware() gin.HandlerFunc {
 	return gin.RecoveryWithWriter(gin.DefaultErrorWriter, func(c *gin.Context, recovered interface{}) {
-		logging.Error("Panic recovered",
+		logging.FromContext(c.Request.Context()).Error("Panic recovered",
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.String("clientIP", c.ClientIP()),
@@ -118,38 +360,147 @@ func (s *Server) zapRecoveryMiddleware() gin.HandlerFunc {
 }
 
 func (s *Server) setupRoutes() {
-	// Health check endpoint
-	s.router.GET("/health", s.healthCheck)
+	// Health check endpoints（/healthは既存のヘルスチェック向けの/readyzの別名）
+	s.router.GET("/livez", s.liveness)
+	s.router.GET("/readyz", s.readiness)
+	s.router.GET("/health", s.readiness)
+
+	// Prometheus metrics endpoint
+	s.router.GET("/metrics", gin.WrapH(metrics.Handler(s.metricsRegistry)))
+
+	// Admin endpoints（ADMIN_TOKENによるBearer認証。未設定の場合は404）
+	admin := s.router.Group("/admin", s.adminHandler.RequireToken())
+	{
+		admin.GET("/log-level", s.adminHandler.GetLogLevels)
+		admin.PUT("/log-level", s.adminHandler.UpdateLogLevel)
+	}
 
 	// API v1 routes
-	v1 := s.router.Group("/api/v1")
+	v1 := s.router.Group("/api/v1", s.rateLimitMiddleware())
 	{
+		// Search endpoint
+		v1.GET("/search", s.searchHandler.Search)
+
 		// Systems endpoints
 		v1.GET("/systems", s.systemsHandler.GetSystems)
 		v1.POST("/systems", s.systemsHandler.CreateSystem)
+		v1.POST("/systems/import", s.systemsHandler.ImportSystems)
 		v1.GET("/systems/:id", s.systemsHandler.GetSystemById)
 		v1.PUT("/systems/:id", s.systemsHandler.UpdateSystem)
 		v1.DELETE("/systems/:id", s.systemsHandler.DeleteSystem)
+
+		// Vendors endpoints
+		v1.GET("/vendors", s.vendorsHandler.GetVendors)
 	}
 }
 
-func (s *Server) Start(addr string) error {
-	return s.router.Run(addr)
-}
+// Run はHTTPサーバーを起動し、ctxがキャンセルされる（SIGTERM等を受信する）と処理中のリクエストを待って停止する
+// 停止時はまずヘルスチェックを失敗させ、DrainDelay後に新規接続の受付を止め、ShutdownTimeoutまで処理中のリクエストの完了を待つ。
+// 戻った時点でリスナーは閉じているため、呼び出し側はその後にDB接続等を閉じてよい。
+func (s *Server) Run(ctx context.Context) error {
+	config := s.httpConfig
+	httpServer := &http.Server{
+		Addr:              ":" + s.appConfig.Port,
+		Handler:           s.router,
+		ReadTimeout:       config.ReadTimeout,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
 
-func (s *Server) healthCheck(c *gin.Context) {
-	isConnected := s.dbClient.IsConnected()
-	
-	// ヘルスチェックログ
-	logging.Debug("Health check requested",
-		zap.Bool("database_connected", isConnected),
-		zap.String("client_ip", c.ClientIP()),
+	s.draining.Store(true)
+	logging.Info("Shutdown signal received, draining",
+		zap.Duration("drainDelay", config.DrainDelay),
+		zap.Duration("shutdownTimeout", config.ShutdownTimeout),
 	)
+	time.Sleep(config.DrainDelay)
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "healthy",
-		"service":   "app-service",
-		"timestamp": time.Now().Format(time.RFC3339),
-		"database":  isConnected,
-	})
-} 
\ No newline at end of file
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		// 期限内に終わらなかったリクエストは接続ごと切断する
+		logging.Warn("Graceful shutdown timed out, closing remaining connections", zap.Error(err))
+		if closeErr := httpServer.Close(); closeErr != nil {
+			return fmt.Errorf("failed to close server: %w", closeErr)
+		}
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+
+	logging.Info("HTTP server stopped")
+	return nil
+}
+
+// liveness はプロセスが応答できるかのみを返す（依存先は確認しない）
+func (s *Server) liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, s.healthResponse(health.StatusOK))
+}
+
+// readiness は登録された依存先のチェックを実行し、1件でも失敗していれば503を返す
+func (s *Server) readiness(c *gin.Context) {
+	// シャットダウン中は新しいリクエストを振り分けられないよう失敗を返す
+	if s.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, s.healthResponse(health.StatusDraining))
+		return
+	}
+
+	report := s.healthRegistry.Run(c.Request.Context())
+
+	response := s.healthResponse(report.Status)
+	checks := make([]healthCheckResult, len(report.Checks))
+	for i, result := range report.Checks {
+		checks[i] = healthCheckResult{
+			Name:      result.Name,
+			Status:    result.Status,
+			LatencyMs: float64(result.Latency.Microseconds()) / 1000,
+		}
+		if result.Error != "" {
+			checks[i].Error = stringPtr(result.Error)
+		}
+	}
+	response.Checks = &checks
+
+	if report.Status != health.StatusOK {
+		logging.Warn("Readiness check failed", zap.Any("checks", report.Checks))
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	logging.Debug("Readiness check passed", zap.String("client_ip", c.ClientIP()))
+	c.JSON(http.StatusOK, response)
+}
+
+// healthCheckResult はModelHealthCheck.Checksの匿名構造体と同じ型
+type healthCheckResult = struct {
+	Error     *string `json:"error,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+}
+
+func (s *Server) healthResponse(status string) appservice.ModelHealthCheck {
+	now := time.Now()
+	return appservice.ModelHealthCheck{
+		Status:    status,
+		Service:   stringPtr("app-service"),
+		Timestamp: &now,
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
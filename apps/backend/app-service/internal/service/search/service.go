@@ -0,0 +1,214 @@
+package search_service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/database"
+	"sample-micro-service-api/package-go/logging"
+	appservice "sample-micro-service-api/package-go/response/app-service"
+	"sample-micro-service-api/package-go/search"
+)
+
+// 検索結果のセクション種別
+const (
+	SectionSystem          = "system"
+	SectionProject         = "project"
+	SectionGroup           = "group"
+	SectionLocalGovernment = "localGovernment"
+)
+
+// searchSection / searchItem はModelSearchResultの匿名構造体と同じ型
+type searchSection = struct {
+	Items []searchItem `json:"items"`
+	Type  string       `json:"type"`
+}
+
+type searchItem = struct {
+	Highlight string  `json:"highlight"`
+	Id        string  `json:"id"`
+	Score     float32 `json:"score"`
+	Subtitle  *string `json:"subtitle"`
+	Title     string  `json:"title"`
+}
+
+// ServiceInterface はSearchServiceのインターフェース
+type ServiceInterface interface {
+	Search(ctx context.Context, query string, limit int) (*appservice.ModelSearchResult, error)
+}
+
+// Service はシステム・プロジェクト・グループ・自治体の横断検索を処理する
+type Service struct {
+	dbClient *database.Client
+}
+
+// NewService はServiceの新しいインスタンスを作成
+func NewService(dbClient *database.Client) ServiceInterface {
+	return &Service{
+		dbClient: dbClient,
+	}
+}
+
+// Search - 横断検索
+// 各エンティティをlimit件まで検索し、空でないセクションを最上位のスコア順に並べて返す
+func (s *Service) Search(ctx context.Context, query string, limit int) (*appservice.ModelSearchResult, error) {
+	logger(ctx).Debug("Service: Searching across entities",
+		zap.String("query", query),
+		zap.Int("limit", limit),
+	)
+
+	searchers := []struct {
+		section string
+		search  func(ctx context.Context, query string, limit int32) ([]searchItem, error)
+	}{
+		{SectionSystem, s.searchSystems},
+		{SectionProject, s.searchProjects},
+		{SectionGroup, s.searchGroups},
+		{SectionLocalGovernment, s.searchLocalGovernments},
+	}
+
+	result := &appservice.ModelSearchResult{
+		Query:    query,
+		Sections: make([]searchSection, 0, len(searchers)),
+	}
+	for _, searcher := range searchers {
+		items, err := searcher.search(ctx, query, int32(limit))
+		if err != nil {
+			logger(ctx).Error("Service: Failed to search",
+				zap.Error(err),
+				zap.String("section", searcher.section),
+				zap.String("query", query),
+			)
+			return nil, fmt.Errorf("failed to search %s: %w", searcher.section, err)
+		}
+		if len(items) == 0 {
+			continue
+		}
+		result.Sections = append(result.Sections, searchSection{Type: searcher.section, Items: items})
+	}
+
+	// 同点の場合は上記の並び（システム、プロジェクト、グループ、自治体）を保つ
+	sort.SliceStable(result.Sections, func(i, j int) bool {
+		return result.Sections[i].Items[0].Score > result.Sections[j].Items[0].Score
+	})
+
+	logger(ctx).Debug("Service: Successfully searched across entities", zap.Int("sections", len(result.Sections)))
+	return result, nil
+}
+
+func (s *Service) searchSystems(ctx context.Context, query string, limit int32) ([]searchItem, error) {
+	rows, err := s.dbClient.Queries.GlobalSearchSystems(ctx, database.GlobalSearchSystemsParams{
+		Query:      query,
+		MaxResults: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]searchItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, searchItem{
+			Id:        row.ID.String(),
+			Title:     row.SystemName,
+			Subtitle:  row.Remark,
+			Highlight: highlight(query, row.SystemName, stringValue(row.SystemNameKana), stringValue(row.Remark)),
+			Score:     row.Score,
+		})
+	}
+	return items, nil
+}
+
+func (s *Service) searchProjects(ctx context.Context, query string, limit int32) ([]searchItem, error) {
+	rows, err := s.dbClient.Queries.GlobalSearchProjects(ctx, database.GlobalSearchProjectsParams{
+		Query:      query,
+		MaxResults: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]searchItem, 0, len(rows))
+	for _, row := range rows {
+		subtitle := fmt.Sprintf("%s（%s）", row.VendorName, row.CorporateNumber)
+		items = append(items, searchItem{
+			Id:        row.ID.String(),
+			Title:     row.ProjectName,
+			Subtitle:  &subtitle,
+			Highlight: highlight(query, row.ProjectName, row.VendorName, row.CorporateNumber),
+			Score:     row.Score,
+		})
+	}
+	return items, nil
+}
+
+func (s *Service) searchGroups(ctx context.Context, query string, limit int32) ([]searchItem, error) {
+	rows, err := s.dbClient.Queries.GlobalSearchGroups(ctx, database.GlobalSearchGroupsParams{
+		Query:      query,
+		MaxResults: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]searchItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, searchItem{
+			Id:        row.ID.String(),
+			Title:     row.GroupName,
+			Highlight: highlight(query, row.GroupName),
+			Score:     row.Score,
+		})
+	}
+	return items, nil
+}
+
+func (s *Service) searchLocalGovernments(ctx context.Context, query string, limit int32) ([]searchItem, error) {
+	rows, err := s.dbClient.Queries.GlobalSearchLocalGovernments(ctx, database.GlobalSearchLocalGovernmentsParams{
+		Query:      query,
+		MaxResults: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]searchItem, 0, len(rows))
+	for _, row := range rows {
+		title := row.PrefectureName + row.CityName
+		kana := row.CityNameKana
+		items = append(items, searchItem{
+			Id:        row.ID,
+			Title:     title,
+			Subtitle:  &kana,
+			Highlight: highlight(query, title, row.CityNameKana),
+			Score:     row.Score,
+		})
+	}
+	return items, nil
+}
+
+// highlight - 最初に一致した項目をハイライトし、どれにも一致しなければタイトル（先頭の項目）を返す
+func highlight(query string, texts ...string) string {
+	for _, text := range texts {
+		if snippet, ok := search.Highlight(text, query); ok {
+			return snippet
+		}
+	}
+	return html.EscapeString(texts[0])
+}
+
+// logger はリクエストのロガー（serviceロガー）を返す
+func logger(ctx context.Context) *zap.Logger {
+	return logging.Named(logging.FromContext(ctx), logging.LoggerService)
+}
+
+// stringValue はNULLを空文字として返す
+func stringValue(s *string) string {
+	if s != nil {
+		return *s
+	}
+	return ""
+}
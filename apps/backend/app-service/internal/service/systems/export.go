@@ -0,0 +1,82 @@
+package systems_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	appservice "sample-micro-service-api/package-go/response/app-service"
+	"sample-micro-service-api/package-go/tracing"
+)
+
+// SystemCursor はDBカーソルから1行ずつシステムを読み出す
+// 全件をメモリに載せずにエクスポートするため、呼び出し側は必ずCloseすること
+type SystemCursor struct {
+	service *Service
+	rows    pgx.Rows
+	current appservice.ModelSystem
+	err     error
+}
+
+// OpenSystemCursor - SearchSystemsDynamicと同じ条件でシステムを読み出すカーソルを開く
+func (s *Service) OpenSystemCursor(ctx context.Context, systemName, email, localGovernmentId string) (*SystemCursor, error) {
+	ctx, span := tracer.Start(ctx, "systems_service.OpenSystemCursor")
+	defer span.End()
+
+	logger(ctx).Debug("Service: Opening system cursor",
+		zap.String("systemName", systemName),
+		zap.String("email", email),
+		zap.String("localGovernmentId", localGovernmentId),
+	)
+
+	query, args := buildSearchSystemsQuery(systemName, email, localGovernmentId)
+
+	rows, err := s.dbClient.Conn.Query(ctx, query, args...)
+	if err != nil {
+		logger(ctx).Error("Service: Failed to open system cursor", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, fmt.Errorf("failed to search systems: %w", err)
+	}
+
+	return &SystemCursor{service: s, rows: rows}, nil
+}
+
+// Next は次の行を読み込み、読み込めた場合にtrueを返す
+func (c *SystemCursor) Next() bool {
+	if c.err != nil || !c.rows.Next() {
+		return false
+	}
+
+	system, err := scanSystem(c.rows)
+	if err != nil {
+		c.err = fmt.Errorf("failed to scan row: %w", err)
+		return false
+	}
+
+	c.current = c.service.convertToModelSystem(system)
+	return true
+}
+
+// System は直前のNextで読み込んだシステムを返す
+func (c *SystemCursor) System() appservice.ModelSystem {
+	return c.current
+}
+
+// Err は読み込み中に発生したエラーを返す
+func (c *SystemCursor) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	if err := c.rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+	return nil
+}
+
+// Close はカーソルを閉じる
+func (c *SystemCursor) Close() error {
+	c.rows.Close()
+	return nil
+}
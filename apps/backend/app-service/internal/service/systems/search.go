@@ -0,0 +1,79 @@
+package systems_service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/database"
+	appservice "sample-micro-service-api/package-go/response/app-service"
+	"sample-micro-service-api/package-go/search"
+	"sample-micro-service-api/package-go/tracing"
+)
+
+// FuzzySearchSystems - システム名・読み仮名のあいまい検索（類似度順）
+// 全角・半角、カタカナ・ひらがなの違いは無視され、一致箇所をハイライトした断片を付与する
+func (s *Service) FuzzySearchSystems(ctx context.Context, query, email, localGovernmentId string, limit int) ([]appservice.ModelSystem, error) {
+	ctx, span := tracer.Start(ctx, "systems_service.FuzzySearchSystems")
+	defer span.End()
+
+	logger(ctx).Debug("Service: Fuzzy searching systems",
+		zap.String("query", query),
+		zap.String("email", email),
+		zap.String("localGovernmentId", localGovernmentId),
+		zap.Int("limit", limit),
+	)
+
+	rows, err := s.dbClient.Queries.FuzzySearchSystems(ctx, database.FuzzySearchSystemsParams{
+		Query:             query,
+		MailAddress:       email,
+		LocalGovernmentID: localGovernmentId,
+		MaxResults:        int32(limit),
+	})
+	if err != nil {
+		logger(ctx).Error("Service: Failed to fuzzy search systems",
+			zap.Error(err),
+			zap.String("query", query),
+		)
+		tracing.RecordError(span, err)
+		return nil, fmt.Errorf("failed to search systems: %w", err)
+	}
+
+	// DBモデルをResponseモデルに変換
+	response := make([]appservice.ModelSystem, 0, len(rows))
+	for _, row := range rows {
+		system := s.convertToModelSystem(database.System{
+			ID:                row.ID,
+			SystemName:        row.SystemName,
+			LocalGovernmentId: row.LocalGovernmentId,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+			MailAddress:       row.MailAddress,
+			Telephone:         row.Telephone,
+			Remark:            row.Remark,
+			SystemNameKana:    row.SystemNameKana,
+			SearchText:        row.SearchText,
+		})
+
+		score := row.Score
+		system.Score = &score
+		system.Highlight = highlightSystem(system, query)
+
+		response = append(response, system)
+	}
+
+	logger(ctx).Debug("Service: Successfully fuzzy searched systems", zap.Int("count", len(response)))
+	return response, nil
+}
+
+// highlightSystem - システム名で一致しなければ読み仮名の一致箇所をハイライト
+func highlightSystem(system appservice.ModelSystem, query string) *string {
+	snippet, ok := search.Highlight(system.SystemName, query)
+	if !ok && system.SystemNameKana != nil {
+		if kanaSnippet, kanaOk := search.Highlight(*system.SystemNameKana, query); kanaOk {
+			snippet = kanaSnippet
+		}
+	}
+	return &snippet
+}
@@ -2,30 +2,38 @@ package systems_service
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/oapi-codegen/runtime/types"
 	"go.uber.org/zap"
 
 	"sample-micro-service-api/package-go/database"
 	"sample-micro-service-api/package-go/logging"
 	appservice "sample-micro-service-api/package-go/response/app-service"
+	"sample-micro-service-api/package-go/tracing"
 )
 
 // ServiceInterface はSystemsServiceのインターフェース
 type ServiceInterface interface {
 	GetSystems(ctx context.Context) ([]appservice.ModelSystem, error)
 	SearchSystems(ctx context.Context, systemName, email, localGovernmentId string) ([]appservice.ModelSystem, error)
+	FuzzySearchSystems(ctx context.Context, query, email, localGovernmentId string, limit int) ([]appservice.ModelSystem, error)
 	SearchSystemsDynamic(ctx context.Context, systemName, email, localGovernmentId string) ([]appservice.ModelSystem, error) // 新しいメソッド追加
 	GetSystemById(ctx context.Context, id string) (*appservice.ModelSystem, error)
 	CreateSystem(ctx context.Context, req appservice.CreateSystemJSONBody) (*appservice.ModelSystem, error)
 	UpdateSystem(ctx context.Context, id string, req appservice.UpdateSystemJSONBody) (*appservice.ModelSystem, error)
 	DeleteSystem(ctx context.Context, id string) error
+	OpenSystemCursor(ctx context.Context, systemName, email, localGovernmentId string) (*SystemCursor, error)
+	ImportSystems(ctx context.Context, file io.Reader, filename, encoding string, dryRun bool) (*appservice.ModelSystemImportResult, error)
 }
 
+// tracer はサービスのメソッドごとのスパンを作成する
+var tracer = tracing.Tracer("systems_service")
+
 // Service はシステム関連のビジネスロジックを処理する
 type Service struct {
 	dbClient *database.Client
@@ -40,11 +48,15 @@ func NewService(dbClient *database.Client) ServiceInterface {
 
 // GetSystems - システム一覧取得
 func (s *Service) GetSystems(ctx context.Context) ([]appservice.ModelSystem, error) {
-	logging.Debug("Service: Getting all systems")
+	ctx, span := tracer.Start(ctx, "systems_service.GetSystems")
+	defer span.End()
+
+	logger(ctx).Debug("Service: Getting all systems")
 	
 	systems, err := s.dbClient.Queries.GetSystems(ctx)
 	if err != nil {
-		logging.Error("Service: Failed to retrieve systems from database", zap.Error(err))
+		logger(ctx).Error("Service: Failed to retrieve systems from database", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, fmt.Errorf("failed to retrieve systems: %w", err)
 	}
 
@@ -54,13 +66,16 @@ func (s *Service) GetSystems(ctx context.Context) ([]appservice.ModelSystem, err
 		response = append(response, s.convertToModelSystem(system))
 	}
 
-	logging.Debug("Service: Successfully retrieved systems", zap.Int("count", len(response)))
+	logger(ctx).Debug("Service: Successfully retrieved systems", zap.Int("count", len(response)))
 	return response, nil
 }
 
 // SearchSystems - システム検索
 func (s *Service) SearchSystems(ctx context.Context, systemName, email, localGovernmentId string) ([]appservice.ModelSystem, error) {
-	logging.Debug("Service: Searching systems",
+	ctx, span := tracer.Start(ctx, "systems_service.SearchSystems")
+	defer span.End()
+
+	logger(ctx).Debug("Service: Searching systems",
 		zap.String("systemName", systemName),
 		zap.String("email", email),
 		zap.String("localGovernmentId", localGovernmentId),
@@ -74,12 +89,13 @@ func (s *Service) SearchSystems(ctx context.Context, systemName, email, localGov
 	
 	systems, err := s.dbClient.Queries.SearchSystems(ctx, params)
 	if err != nil {
-		logging.Error("Service: Failed to search systems", 
+		logger(ctx).Error("Service: Failed to search systems", 
 			zap.Error(err),
 			zap.String("systemName", systemName),
 			zap.String("email", email),
 			zap.String("localGovernmentId", localGovernmentId),
 		)
+		tracing.RecordError(span, err)
 		return nil, fmt.Errorf("failed to search systems: %w", err)
 	}
 
@@ -89,15 +105,54 @@ func (s *Service) SearchSystems(ctx context.Context, systemName, email, localGov
 		response = append(response, s.convertToModelSystem(system))
 	}
 
-	logging.Debug("Service: Successfully searched systems", zap.Int("count", len(response)))
+	logger(ctx).Debug("Service: Successfully searched systems", zap.Int("count", len(response)))
 	return response, nil
 }
 
 // SearchSystemsDynamic - システム検索（動的SQL構築版サンプル）
 func (s *Service) SearchSystemsDynamic(ctx context.Context, systemName, email, localGovernmentId string) ([]appservice.ModelSystem, error) {
-	baseQuery := `
+	ctx, span := tracer.Start(ctx, "systems_service.SearchSystemsDynamic")
+	defer span.End()
+
+	query, args := buildSearchSystemsQuery(systemName, email, localGovernmentId)
+	
+	// 実行
+	rows, err := s.dbClient.Conn.Query(ctx, query, args...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, fmt.Errorf("failed to search systems: %w", err)
+	}
+	defer rows.Close()
+	
+	// 結果の処理
+	var systems []database.System
+	for rows.Next() {
+		system, err := scanSystem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		systems = append(systems, system)
+	}
+	
+	if err := rows.Err(); err != nil {
+		tracing.RecordError(span, err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// DBモデルをResponseモデルに変換
+	var response []appservice.ModelSystem
+	for _, system := range systems {
+		response = append(response, s.convertToModelSystem(system))
+	}
+
+	return response, nil
+}
+
+// buildSearchSystemsQuery - 指定された条件のみでWHERE句を組み立てた検索クエリを作成
+func buildSearchSystemsQuery(systemName, email, localGovernmentId string) (string, []interface{}) {
+	baseQuery := `-- name: SearchSystemsDynamic :many
 		SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-		       "mailAddress", telephone, remark
+		       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 		FROM public.system
 	`
 	
@@ -130,92 +185,83 @@ func (s *Service) SearchSystemsDynamic(ctx context.Context, systemName, email, l
 	}
 	
 	baseQuery += ` ORDER BY "createdAt" DESC`
-	
-	// 実行
-	rows, err := s.dbClient.DB.QueryContext(ctx, baseQuery, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search systems: %w", err)
-	}
-	defer rows.Close()
-	
-	// 結果の処理
-	var systems []database.System
-	for rows.Next() {
-		var system database.System
-		err := rows.Scan(
-			&system.ID,
-			&system.SystemName,
-			&system.LocalGovernmentId,
-			&system.CreatedAt,
-			&system.UpdatedAt,
-			&system.MailAddress,
-			&system.Telephone,
-			&system.Remark,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
-		systems = append(systems, system)
-	}
-	
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
-	}
 
-	// DBモデルをResponseモデルに変換
-	var response []appservice.ModelSystem
-	for _, system := range systems {
-		response = append(response, s.convertToModelSystem(system))
-	}
+	return baseQuery, args
+}
 
-	return response, nil
+// scanSystem - 検索クエリの1行をDBモデルに読み込む
+func scanSystem(rows pgx.Rows) (database.System, error) {
+	var system database.System
+	err := rows.Scan(
+		&system.ID,
+		&system.SystemName,
+		&system.LocalGovernmentId,
+		&system.CreatedAt,
+		&system.UpdatedAt,
+		&system.MailAddress,
+		&system.Telephone,
+		&system.Remark,
+		&system.SystemNameKana,
+		&system.SearchText,
+	)
+	return system, err
 }
 
 // GetSystemById - システム詳細取得
 func (s *Service) GetSystemById(ctx context.Context, id string) (*appservice.ModelSystem, error) {
-	logging.Debug("Service: Getting system by ID", zap.String("id", id))
+	ctx, span := tracer.Start(ctx, "systems_service.GetSystemById")
+	defer span.End()
+
+	logger(ctx).Debug("Service: Getting system by ID", zap.String("id", id))
 	
 	systemId, err := uuid.Parse(id)
 	if err != nil {
-		logging.Warn("Service: Invalid system ID format", zap.String("id", id), zap.Error(err))
+		logger(ctx).Warn("Service: Invalid system ID format", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, fmt.Errorf("invalid system ID format: %w", err)
 	}
 
 	system, err := s.dbClient.Queries.GetSystem(ctx, systemId)
 	if err != nil {
-		logging.Warn("Service: System not found in database", zap.String("id", id), zap.Error(err))
+		logger(ctx).Warn("Service: System not found in database", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, fmt.Errorf("system not found: %w", err)
 	}
 
 	response := s.convertToModelSystem(system)
-	logging.Debug("Service: Successfully retrieved system", zap.String("id", id))
+	logger(ctx).Debug("Service: Successfully retrieved system", zap.String("id", id))
 	return &response, nil
 }
 
 // CreateSystem - システム作成
 func (s *Service) CreateSystem(ctx context.Context, req appservice.CreateSystemJSONBody) (*appservice.ModelSystem, error) {
-	logging.Info("Service: Creating new system", zap.String("systemName", req.SystemName))
+	ctx, span := tracer.Start(ctx, "systems_service.CreateSystem")
+	defer span.End()
+
+	logger(ctx).Info("Service: Creating new system", zap.String("systemName", req.SystemName))
 	
 	// DB用のパラメータを準備
 	params := database.CreateSystemParams{
 		SystemName:        req.SystemName,
-		LocalGovernmentId: ptrToNullString(req.LocalGovernmentId),
+		LocalGovernmentId: req.LocalGovernmentId,
 		MailAddress:       string(req.MailAddress),
-		Telephone:         ptrToNullString(req.Telephone),
-		Remark:            ptrToNullString(req.Remark),
+		Telephone:         req.Telephone,
+		Remark:            req.Remark,
+		SystemNameKana:    req.SystemNameKana,
 	}
 
 	system, err := s.dbClient.Queries.CreateSystem(ctx, params)
 	if err != nil {
-		logging.Error("Service: Failed to create system", 
+		logger(ctx).Error("Service: Failed to create system", 
 			zap.Error(err),
 			zap.String("systemName", req.SystemName),
 		)
+		tracing.RecordError(span, err)
 		return nil, fmt.Errorf("failed to create system: %w", err)
 	}
 
 	response := s.convertToModelSystem(system)
-	logging.Info("Service: Successfully created system", 
+	logger(ctx).Info("Service: Successfully created system", 
 		zap.String("id", system.ID.String()),
 		zap.String("systemName", req.SystemName),
 	)
@@ -224,14 +270,18 @@ func (s *Service) CreateSystem(ctx context.Context, req appservice.CreateSystemJ
 
 // UpdateSystem - システム更新
 func (s *Service) UpdateSystem(ctx context.Context, id string, req appservice.UpdateSystemJSONBody) (*appservice.ModelSystem, error) {
-	logging.Info("Service: Updating system", 
+	ctx, span := tracer.Start(ctx, "systems_service.UpdateSystem")
+	defer span.End()
+
+	logger(ctx).Info("Service: Updating system", 
 		zap.String("id", id),
 		zap.String("systemName", req.SystemName),
 	)
 	
 	systemId, err := uuid.Parse(id)
 	if err != nil {
-		logging.Warn("Service: Invalid system ID format for update", zap.String("id", id), zap.Error(err))
+		logger(ctx).Warn("Service: Invalid system ID format for update", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, fmt.Errorf("invalid system ID format: %w", err)
 	}
 
@@ -239,46 +289,53 @@ func (s *Service) UpdateSystem(ctx context.Context, id string, req appservice.Up
 	params := database.UpdateSystemParams{
 		ID:                systemId,
 		SystemName:        req.SystemName,
-		LocalGovernmentId: ptrToNullString(req.LocalGovernmentId),
+		LocalGovernmentId: req.LocalGovernmentId,
 		MailAddress:       string(req.MailAddress),
-		Telephone:         ptrToNullString(req.Telephone),
-		Remark:            ptrToNullString(req.Remark),
+		Telephone:         req.Telephone,
+		Remark:            req.Remark,
+		SystemNameKana:    req.SystemNameKana,
 	}
 
 	system, err := s.dbClient.Queries.UpdateSystem(ctx, params)
 	if err != nil {
-		logging.Error("Service: Failed to update system", 
+		logger(ctx).Error("Service: Failed to update system", 
 			zap.String("id", id),
 			zap.Error(err),
 		)
+		tracing.RecordError(span, err)
 		return nil, fmt.Errorf("system not found or failed to update: %w", err)
 	}
 
 	response := s.convertToModelSystem(system)
-	logging.Info("Service: Successfully updated system", zap.String("id", id))
+	logger(ctx).Info("Service: Successfully updated system", zap.String("id", id))
 	return &response, nil
 }
 
 // DeleteSystem - システム削除
 func (s *Service) DeleteSystem(ctx context.Context, id string) error {
-	logging.Info("Service: Deleting system", zap.String("id", id))
+	ctx, span := tracer.Start(ctx, "systems_service.DeleteSystem")
+	defer span.End()
+
+	logger(ctx).Info("Service: Deleting system", zap.String("id", id))
 	
 	systemId, err := uuid.Parse(id)
 	if err != nil {
-		logging.Warn("Service: Invalid system ID format for deletion", zap.String("id", id), zap.Error(err))
+		logger(ctx).Warn("Service: Invalid system ID format for deletion", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
 		return fmt.Errorf("invalid system ID format: %w", err)
 	}
 
 	err = s.dbClient.Queries.DeleteSystem(ctx, systemId)
 	if err != nil {
-		logging.Error("Service: Failed to delete system", 
+		logger(ctx).Error("Service: Failed to delete system", 
 			zap.String("id", id),
 			zap.Error(err),
 		)
+		tracing.RecordError(span, err)
 		return fmt.Errorf("system not found: %w", err)
 	}
 
-	logging.Info("Service: Successfully deleted system", zap.String("id", id))
+	logger(ctx).Info("Service: Successfully deleted system", zap.String("id", id))
 	return nil
 }
 
@@ -287,26 +344,17 @@ func (s *Service) convertToModelSystem(system database.System) appservice.ModelS
 	return appservice.ModelSystem{
 		Id:                system.ID,
 		SystemName:        system.SystemName,
-		LocalGovernmentId: nullStringToPtr(system.LocalGovernmentId),
+		LocalGovernmentId: system.LocalGovernmentId,
 		CreatedAt:         system.CreatedAt,
 		UpdatedAt:         system.UpdatedAt,
 		MailAddress:       types.Email(system.MailAddress),
-		Telephone:         nullStringToPtr(system.Telephone),
-		Remark:            nullStringToPtr(system.Remark),
+		Telephone:         system.Telephone,
+		Remark:            system.Remark,
+		SystemNameKana:    system.SystemNameKana,
 	}
 }
 
-// ヘルパー関数
-func nullStringToPtr(ns sql.NullString) *string {
-	if ns.Valid {
-		return &ns.String
-	}
-	return nil
+// logger はリクエストのロガー（serviceロガー）を返す
+func logger(ctx context.Context) *zap.Logger {
+	return logging.Named(logging.FromContext(ctx), logging.LoggerService)
 }
-
-func ptrToNullString(s *string) sql.NullString {
-	if s != nil {
-		return sql.NullString{String: *s, Valid: true}
-	}
-	return sql.NullString{Valid: false}
-} 
\ No newline at end of file
@@ -0,0 +1,123 @@
+package vendors_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/database"
+	"sample-micro-service-api/package-go/logging"
+	appservice "sample-micro-service-api/package-go/response/app-service"
+)
+
+// vendorProject / vendorSystem はModelVendorの匿名構造体と同じ型
+type vendorProject = struct {
+	Id                openapi_types.UUID `json:"id"`
+	LocalGovernmentId string             `json:"localGovernmentId"`
+	ProjectName       string             `json:"projectName"`
+}
+
+type vendorSystem = struct {
+	Id         openapi_types.UUID `json:"id"`
+	SystemName string             `json:"systemName"`
+}
+
+// ServiceInterface はVendorsServiceのインターフェース
+type ServiceInterface interface {
+	GetVendors(ctx context.Context, corporateNumber string) ([]appservice.ModelVendor, error)
+}
+
+// Service はベンダー関連のビジネスロジックを処理する
+type Service struct {
+	dbClient *database.Client
+}
+
+// NewService はServiceの新しいインスタンスを作成
+func NewService(dbClient *database.Client) ServiceInterface {
+	return &Service{
+		dbClient: dbClient,
+	}
+}
+
+// GetVendors - ベンダー一覧取得（各ベンダーのプロジェクト・システムを含む）
+// corporateNumberが空でなければそのベンダーのみを返す
+func (s *Service) GetVendors(ctx context.Context, corporateNumber string) ([]appservice.ModelVendor, error) {
+	logger(ctx).Debug("Service: Getting vendors", zap.String("corporateNumber", corporateNumber))
+
+	vendors, err := s.dbClient.Queries.ListVendors(ctx, corporateNumber)
+	if err != nil {
+		logger(ctx).Error("Service: Failed to retrieve vendors from database", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve vendors: %w", err)
+	}
+
+	projects, err := s.dbClient.Queries.ListVendorProjects(ctx, corporateNumber)
+	if err != nil {
+		logger(ctx).Error("Service: Failed to retrieve vendor projects from database", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve vendor projects: %w", err)
+	}
+
+	systems, err := s.dbClient.Queries.ListVendorSystems(ctx, corporateNumber)
+	if err != nil {
+		logger(ctx).Error("Service: Failed to retrieve vendor systems from database", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve vendor systems: %w", err)
+	}
+
+	// DBモデルをResponseモデルに変換し、法人番号ごとにプロジェクト・システムをまとめる
+	response := make([]appservice.ModelVendor, 0, len(vendors))
+	index := make(map[string]int, len(vendors))
+	for _, vendor := range vendors {
+		index[vendor.CorporateNumber] = len(response)
+		response = append(response, convertToModelVendor(vendor))
+	}
+	for _, project := range projects {
+		if i, ok := index[project.CorporateNumber]; ok {
+			response[i].Projects = append(response[i].Projects, vendorProject{
+				Id:                project.ID,
+				LocalGovernmentId: project.LocalGovernmentId,
+				ProjectName:       project.ProjectName,
+			})
+		}
+	}
+	for _, system := range systems {
+		if i, ok := index[system.CorporateNumber]; ok {
+			response[i].Systems = append(response[i].Systems, vendorSystem{
+				Id:         system.ID,
+				SystemName: system.SystemName,
+			})
+		}
+	}
+
+	logger(ctx).Debug("Service: Successfully retrieved vendors", zap.Int("count", len(response)))
+	return response, nil
+}
+
+func convertToModelVendor(vendor database.VendorDirectory) appservice.ModelVendor {
+	return appservice.ModelVendor{
+		CorporateNumber: vendor.CorporateNumber,
+		VendorName:      vendor.VendorName,
+		VendorNameKana:  vendor.VendorNameKana,
+		PrefectureName:  vendor.PrefectureName,
+		CityName:        vendor.CityName,
+		StreetNumber:    vendor.StreetNumber,
+		PostCode:        vendor.PostCode,
+		CloseDate:       timeToDate(vendor.CloseDate),
+		NtaUpdatedAt:    timeToDate(vendor.NtaUpdatedAt),
+		Projects:        []vendorProject{},
+		Systems:         []vendorSystem{},
+	}
+}
+
+// logger はリクエストのロガー（serviceロガー）を返す
+func logger(ctx context.Context) *zap.Logger {
+	return logging.Named(logging.FromContext(ctx), logging.LoggerService)
+}
+
+func timeToDate(t *time.Time) *openapi_types.Date {
+	if t != nil {
+		return &openapi_types.Date{Time: *t}
+	}
+	return nil
+}
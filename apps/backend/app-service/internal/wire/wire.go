@@ -4,39 +4,135 @@
 package wire
 
 import (
+	"context"
+
 	"sample-micro-service-api/apps/backend/app-service/internal"
+	adminHandler "sample-micro-service-api/apps/backend/app-service/internal/handler/admin"
+	searchHandler "sample-micro-service-api/apps/backend/app-service/internal/handler/search"
 	systemsHandler "sample-micro-service-api/apps/backend/app-service/internal/handler/systems"
+	vendorsHandler "sample-micro-service-api/apps/backend/app-service/internal/handler/vendors"
+	searchService "sample-micro-service-api/apps/backend/app-service/internal/service/search"
 	systemsService "sample-micro-service-api/apps/backend/app-service/internal/service/systems"
+	vendorsService "sample-micro-service-api/apps/backend/app-service/internal/service/vendors"
+	"sample-micro-service-api/package-go/config"
 	"sample-micro-service-api/package-go/database"
+	"sample-micro-service-api/package-go/health"
+	"sample-micro-service-api/package-go/logging"
+	"sample-micro-service-api/package-go/metrics"
+	"sample-micro-service-api/package-go/ratelimit"
+	"sample-micro-service-api/package-go/tracing"
 
 	"github.com/google/wire"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 // ProvideDatabaseClient は database.NewClient をラップしてクリーンアップ機能を提供
-func ProvideDatabaseClient() (*database.Client, func(), error) {
-	client, err := database.NewClient()
+// sqlcクエリごとの実行時間と接続プールの状態をメトリクスに登録し、クエリごとのスパンを記録する
+// AUTO_MIGRATE・MIGRATION_STRICTの場合は接続後にスキーマを確認する（database.Client.PrepareSchema）
+// キャッシュが有効な場合はマスタとシステムの参照をキャッシュし、CACHE_NOTIFYでは他のインスタンスの書き込みを購読する
+func ProvideDatabaseClient(cfg config.DatabaseConfig, cacheCfg config.CacheConfig, registry *prometheus.Registry, queryMetrics *metrics.QueryMetrics) (*database.Client, func(), error) {
+	client, err := database.NewClient(cfg, queryMetrics.Hook, tracing.QueryHook, database.LogQueryHook)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := client.PrepareSchema(context.Background(), cfg); err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	metrics.RegisterPoolStats(registry, client.Pool, "app")
+	for name, pool := range client.ReplicaPools() {
+		metrics.RegisterPoolStats(registry, pool, name)
+	}
+
+	stopListening := func() error { return nil }
+	if cacheCfg.Enabled {
+		cached := client.EnableCache(cacheCfg)
+		for name, c := range cached.Caches() {
+			metrics.RegisterCache(registry, name, c)
+		}
+
+		if cacheCfg.Notify {
+			stopListening, err = client.ListenCacheInvalidation()
+			if err != nil {
+				client.Close()
+				return nil, nil, err
+			}
+		}
+	}
 	
 	cleanup := func() {
+		stopListening()
 		client.Close()
 	}
 	
 	return client, cleanup, nil
 }
 
+// ProvideHealthRegistry はレディネスチェック（DB Ping、マイグレーションのバージョン、接続プールの飽和）を登録
+// マイグレーションの最新バージョンはバイナリに埋め込んだマイグレーションファイルから求める
+func ProvideHealthRegistry(cfg config.HealthConfig, client *database.Client) *health.Registry {
+	registry := health.NewRegistry(cfg.CheckTimeout)
+	registry.Register("database", client.PingChecker())
+
+	if cfg.CheckMigrations {
+		expected, err := database.LatestMigrationVersion()
+		if err != nil {
+			logging.Warn("Skipping migration readiness check", zap.Error(err))
+		} else {
+			registry.Register("migrations", client.MigrationChecker(expected))
+		}
+	}
+
+	registry.Register("connectionPool", client.PoolChecker(cfg.PoolSaturation))
+	return registry
+}
+
 // Providers
+// ConfigSet は設定の各セクションを個別に注入できるようにする
+var ConfigSet = wire.NewSet(
+	wire.FieldsOf(new(*config.Config), "App", "Log", "Database", "HTTP", "Health", "Admin", "RateLimit", "CORS", "Compression", "Cache"),
+)
+
+// ProvideHTTPMetrics はHTTPリクエストのメトリクスをregistryに登録
+func ProvideHTTPMetrics(registry *prometheus.Registry) *metrics.HTTPMetrics {
+	return metrics.NewHTTPMetrics(registry)
+}
+
+// ProvideQueryMetrics はsqlcクエリのメトリクスをregistryに登録
+func ProvideQueryMetrics(registry *prometheus.Registry) *metrics.QueryMetrics {
+	return metrics.NewQueryMetrics(registry)
+}
+
+// RateLimitSet はレート制限のストア（既定はインスタンスごとのメモリ）とLimiterを提供する
+var RateLimitSet = wire.NewSet(
+	ratelimit.NewMemoryStore,
+	wire.Bind(new(ratelimit.Store), new(*ratelimit.MemoryStore)),
+	ratelimit.NewLimiter,
+)
+
+var MetricsSet = wire.NewSet(
+	metrics.NewRegistry,
+	ProvideHTTPMetrics,
+	ProvideQueryMetrics,
+)
+
 var DatabaseSet = wire.NewSet(
 	ProvideDatabaseClient,
+	ProvideHealthRegistry,
 )
 
 var ServiceSet = wire.NewSet(
 	systemsService.NewService,
+	searchService.NewService,
+	vendorsService.NewService,
 )
 
 var HandlerSet = wire.NewSet(
 	systemsHandler.NewHandler,
+	searchHandler.NewHandler,
+	vendorsHandler.NewHandler,
+	adminHandler.NewHandler,
 )
 
 var ServerSet = wire.NewSet(
@@ -45,6 +141,9 @@ var ServerSet = wire.NewSet(
 
 // Wire everything together
 var AppSet = wire.NewSet(
+	ConfigSet,
+	MetricsSet,
+	RateLimitSet,
 	DatabaseSet,
 	ServiceSet,
 	HandlerSet,
@@ -52,7 +151,7 @@ var AppSet = wire.NewSet(
 )
 
 // InitializeApp は Wire によって自動生成される関数
-func InitializeApp() (*internal.Server, func(), error) {
+func InitializeApp(cfg *config.Config) (*internal.Server, func(), error) {
 	wire.Build(AppSet)
 	return nil, nil, nil
 } 
\ No newline at end of file
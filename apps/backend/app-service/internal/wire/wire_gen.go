@@ -7,24 +7,62 @@
 package wire
 
 import (
+	"context"
 	"github.com/google/wire"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 	"sample-micro-service-api/apps/backend/app-service/internal"
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/admin"
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/search"
 	"sample-micro-service-api/apps/backend/app-service/internal/handler/systems"
+	"sample-micro-service-api/apps/backend/app-service/internal/handler/vendors"
+	"sample-micro-service-api/apps/backend/app-service/internal/service/search"
 	"sample-micro-service-api/apps/backend/app-service/internal/service/systems"
+	"sample-micro-service-api/apps/backend/app-service/internal/service/vendors"
+	"sample-micro-service-api/package-go/config"
 	"sample-micro-service-api/package-go/database"
+	"sample-micro-service-api/package-go/health"
+	"sample-micro-service-api/package-go/logging"
+	"sample-micro-service-api/package-go/metrics"
+	"sample-micro-service-api/package-go/ratelimit"
+	"sample-micro-service-api/package-go/tracing"
 )
 
 // Injectors from wire.go:
 
 // InitializeApp は Wire によって自動生成される関数
-func InitializeApp() (*internal.Server, func(), error) {
-	client, cleanup, err := ProvideDatabaseClient()
+func InitializeApp(cfg *config.Config) (*internal.Server, func(), error) {
+	appConfig := cfg.App
+	httpConfig := cfg.HTTP
+	corsConfig := cfg.CORS
+	compressionConfig := cfg.Compression
+	databaseConfig := cfg.Database
+	cacheConfig := cfg.Cache
+	registry := metrics.NewRegistry()
+	queryMetrics := ProvideQueryMetrics(registry)
+	client, cleanup, err := ProvideDatabaseClient(databaseConfig, cacheConfig, registry, queryMetrics)
 	if err != nil {
 		return nil, nil, err
 	}
+	healthConfig := cfg.Health
+	healthRegistry := ProvideHealthRegistry(healthConfig, client)
+	httpMetrics := ProvideHTTPMetrics(registry)
+	rateLimitConfig := cfg.RateLimit
+	memoryStore := ratelimit.NewMemoryStore()
+	limiter, err := ratelimit.NewLimiter(rateLimitConfig, memoryStore)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
 	serviceInterface := systems_service.NewService(client)
 	handler := systems_handler.NewHandler(serviceInterface)
-	server := internal.NewServer(client, handler)
+	search_serviceServiceInterface := search_service.NewService(client)
+	search_handlerHandler := search_handler.NewHandler(search_serviceServiceInterface)
+	vendors_serviceServiceInterface := vendors_service.NewService(client)
+	vendors_handlerHandler := vendors_handler.NewHandler(vendors_serviceServiceInterface)
+	adminConfig := cfg.Admin
+	admin_handlerHandler := admin_handler.NewHandler(adminConfig)
+	server := internal.NewServer(appConfig, httpConfig, corsConfig, compressionConfig, databaseConfig, client, healthRegistry, registry, httpMetrics, limiter, handler, search_handlerHandler, vendors_handlerHandler, admin_handlerHandler)
 	return server, func() {
 		cleanup()
 	}, nil
@@ -33,32 +71,103 @@ func InitializeApp() (*internal.Server, func(), error) {
 // wire.go:
 
 // ProvideDatabaseClient は database.NewClient をラップしてクリーンアップ機能を提供
-func ProvideDatabaseClient() (*database.Client, func(), error) {
-	client, err := database.NewClient()
+// sqlcクエリごとの実行時間と接続プールの状態をメトリクスに登録し、クエリごとのスパンを記録する
+// AUTO_MIGRATE・MIGRATION_STRICTの場合は接続後にスキーマを確認する（database.Client.PrepareSchema）
+// キャッシュが有効な場合はマスタとシステムの参照をキャッシュし、CACHE_NOTIFYでは他のインスタンスの書き込みを購読する
+func ProvideDatabaseClient(cfg config.DatabaseConfig, cacheCfg config.CacheConfig, registry *prometheus.Registry, queryMetrics *metrics.QueryMetrics) (*database.Client, func(), error) {
+	client, err := database.NewClient(cfg, queryMetrics.Hook, tracing.QueryHook, database.LogQueryHook)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := client.PrepareSchema(context.Background(), cfg); err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	metrics.RegisterPoolStats(registry, client.Pool, "app")
+	for name, pool := range client.ReplicaPools() {
+		metrics.RegisterPoolStats(registry, pool, name)
+	}
+
+	stopListening := func() error { return nil }
+	if cacheCfg.Enabled {
+		cached := client.EnableCache(cacheCfg)
+		for name, c := range cached.Caches() {
+			metrics.RegisterCache(registry, name, c)
+		}
+
+		if cacheCfg.Notify {
+			stopListening, err = client.ListenCacheInvalidation()
+			if err != nil {
+				client.Close()
+				return nil, nil, err
+			}
+		}
+	}
 
 	cleanup := func() {
+		stopListening()
 		client.Close()
 	}
 
 	return client, cleanup, nil
 }
 
+// ProvideHealthRegistry はレディネスチェック（DB Ping、マイグレーションのバージョン、接続プールの飽和）を登録
+// マイグレーションの最新バージョンはバイナリに埋め込んだマイグレーションファイルから求める
+func ProvideHealthRegistry(cfg config.HealthConfig, client *database.Client) *health.Registry {
+	registry := health.NewRegistry(cfg.CheckTimeout)
+	registry.Register("database", client.PingChecker())
+
+	if cfg.CheckMigrations {
+		expected, err := database.LatestMigrationVersion()
+		if err != nil {
+			logging.Warn("Skipping migration readiness check", zap.Error(err))
+		} else {
+			registry.Register("migrations", client.MigrationChecker(expected))
+		}
+	}
+
+	registry.Register("connectionPool", client.PoolChecker(cfg.PoolSaturation))
+	return registry
+}
+
 // Providers
+// ConfigSet は設定の各セクションを個別に注入できるようにする
+var ConfigSet = wire.NewSet(wire.FieldsOf(new(*config.Config), "App", "Log", "Database", "HTTP", "Health", "Admin", "RateLimit", "CORS", "Compression", "Cache"))
+
+// ProvideHTTPMetrics はHTTPリクエストのメトリクスをregistryに登録
+func ProvideHTTPMetrics(registry *prometheus.Registry) *metrics.HTTPMetrics {
+	return metrics.NewHTTPMetrics(registry)
+}
+
+// ProvideQueryMetrics はsqlcクエリのメトリクスをregistryに登録
+func ProvideQueryMetrics(registry *prometheus.Registry) *metrics.QueryMetrics {
+	return metrics.NewQueryMetrics(registry)
+}
+
+// RateLimitSet はレート制限のストア（既定はインスタンスごとのメモリ）とLimiterを提供する
+var RateLimitSet = wire.NewSet(ratelimit.NewMemoryStore, wire.Bind(new(ratelimit.Store), new(*ratelimit.MemoryStore)), ratelimit.NewLimiter)
+
+var MetricsSet = wire.NewSet(metrics.NewRegistry, ProvideHTTPMetrics,
+	ProvideQueryMetrics,
+)
+
 var DatabaseSet = wire.NewSet(
 	ProvideDatabaseClient,
+	ProvideHealthRegistry,
 )
 
-var ServiceSet = wire.NewSet(systems_service.NewService)
+var ServiceSet = wire.NewSet(systems_service.NewService, search_service.NewService, vendors_service.NewService)
 
-var HandlerSet = wire.NewSet(systems_handler.NewHandler)
+var HandlerSet = wire.NewSet(systems_handler.NewHandler, search_handler.NewHandler, vendors_handler.NewHandler, admin_handler.NewHandler)
 
 var ServerSet = wire.NewSet(internal.NewServer)
 
 // Wire everything together
 var AppSet = wire.NewSet(
+	ConfigSet,
+	MetricsSet,
+	RateLimitSet,
 	DatabaseSet,
 	ServiceSet,
 	HandlerSet,
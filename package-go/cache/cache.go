@@ -0,0 +1,154 @@
+// Package cache はTTLと最大件数を持つインメモリのLRUキャッシュを提供する
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Stats はキャッシュの統計
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // 最大件数を超えたために削除した件数
+	Entries   int
+}
+
+// Cache はTTLと最大件数を持つLRUキャッシュ
+// 同じキーの同時の読み込みは1回にまとめ（singleflight）、Purge以前に始まった読み込みの結果は保存しない。
+type Cache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // 先頭が最近使われたエントリ
+	generation uint64     // Purgeのたびに増やし、それ以前に始まった読み込みの結果を捨てる
+
+	group     singleflight.Group
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+type entry struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+// New はCacheを作成（maxEntriesが0以下の場合は件数を制限しない）
+func New(ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Load はkeyのキャッシュを返し、なければloadで読み込んで保存する
+// loadのエラーはキャッシュしない。読み込みは最初の呼び出し元のキャンセルの影響を受けない。
+func Load[T any](ctx context.Context, c *Cache, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if value, ok := c.get(key); ok {
+		c.hits.Add(1)
+		return value.(T), nil
+	}
+	c.misses.Add(1)
+
+	generation := c.currentGeneration()
+	flightKey := strconv.FormatUint(generation, 10) + "|" + key
+	value, err, _ := c.group.Do(flightKey, func() (any, error) {
+		value, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		c.set(key, value, generation)
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value.(T), nil
+}
+
+// Purge はすべてのエントリを削除する
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// Stats はキャッシュの統計を返す
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+func (c *Cache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	if time.Now().After(e.expires) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return e.value, true
+}
+
+func (c *Cache) set(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 読み込み中にPurgeされた場合、結果は古い可能性があるため保存しない
+	if generation != c.generation {
+		return
+	}
+
+	expires := time.Now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		e := element.Value.(*entry)
+		e.value, e.expires = value, expires
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&entry{key: key, value: value, expires: expires})
+	if c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+		c.evictions.Add(1)
+	}
+}
+
+func (c *Cache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
@@ -0,0 +1,210 @@
+// Package config はアプリケーション全体の設定を型付きで読み込む
+//
+// 値は 環境変数 > .envファイル > YAMLファイル > 既定値 の優先順で決まる。
+// 各フィールドのタグで読み込み元を指定する。
+//   - env:      環境変数・.envファイルのキー
+//   - yaml:     YAMLファイルのキー（セクションのキーと組み合わせて "database.url" のように参照）
+//   - default:  未設定時の既定値
+//   - required: "true" の場合、未設定ならエラー
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config はアプリケーションの設定
+type Config struct {
+	App         AppConfig         `yaml:"app"`
+	Log         LogConfig         `yaml:"log"`
+	Database    DatabaseConfig    `yaml:"database"`
+	HTTP        HTTPConfig        `yaml:"http"`
+	Health      HealthConfig      `yaml:"health"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Admin       AdminConfig       `yaml:"admin"`
+	RateLimit   RateLimitConfig   `yaml:"rateLimit"`
+	CORS        CORSConfig        `yaml:"cors"`
+	Compression CompressionConfig `yaml:"compression"`
+	Cache       CacheConfig       `yaml:"cache"`
+}
+
+// AppConfig は実行環境とHTTPサーバーの待ち受け設定
+type AppConfig struct {
+	Environment string `env:"APP_ENV" yaml:"environment" default:"development"` // development, staging, production
+	Port        string `env:"PORT" yaml:"port" default:"8080"`
+	GinMode     string `env:"GIN_MODE" yaml:"ginMode" default:"release"` // debug, release, test
+}
+
+// LogConfig はログ出力の設定
+type LogConfig struct {
+	Level              string `env:"LOG_LEVEL" yaml:"level" default:"info"`                           // debug, info, warn, error
+	ProjectID          string `env:"GOOGLE_CLOUD_PROJECT" yaml:"projectId"`                           // ログとトレースを関連付ける logging.googleapis.com/trace の "projects/<ID>/traces/..." に使う
+	SamplingInitial    int    `env:"LOG_SAMPLING_INITIAL" yaml:"samplingInitial" default:"0"`         // 1秒ごと・重要度とメッセージの組ごとに必ず出力する件数（0でサンプリングしない）
+	SamplingThereafter int    `env:"LOG_SAMPLING_THEREAFTER" yaml:"samplingThereafter" default:"100"` // SamplingInitialを超えた後、何件に1件を出力するか
+
+	// 個人情報のマスク（フィールド名・クエリパラメータ名は大文字・小文字を区別しない）
+	RedactFields      []string `env:"LOG_REDACT_FIELDS" yaml:"redactFields" default:"email,mailAddress,telephone,phone,tel"`            // 値を出力しないフィールド名
+	RedactQueryParams []string `env:"LOG_REDACT_QUERY_PARAMS" yaml:"redactQueryParams" default:"email,mailAddress,telephone,phone,tel"` // requestUrl等から値を取り除くクエリパラメータ名
+	RedactPatterns    bool     `env:"LOG_REDACT_PATTERNS" yaml:"redactPatterns" default:"true"`                                         // 文字列中のメールアドレス・電話番号をマスクするか
+
+	// 名前付きロガー（handler, service, database）ごとのレベル（"handler=debug,database=warn" の形式）。未指定のロガーはLevelに従う
+	Levels []string `env:"LOG_LEVELS" yaml:"levels"`
+}
+
+// DatabaseConfig はデータベース接続とマイグレーションの設定
+type DatabaseConfig struct {
+	URL          string `env:"POSTGRES_URL" yaml:"url" required:"true"`
+	MigrationDir string `env:"MIGRATION_DIR" yaml:"migrationDir" default:"migrations"` // create で新しいマイグレーションを作成するディレクトリ（適用には埋め込んだファイルを使う）
+
+	// 接続プール（pgxpool。Cloud SQLの最大接続数をインスタンス数で割った値以下にする）
+	MaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" yaml:"maxOpenConns" default:"20"`        // 最大接続数
+	MinConns        int           `env:"DB_MIN_CONNS" yaml:"minConns" default:"2"`                  // アイドル状態でも保持する最小接続数
+	ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" yaml:"connMaxLifetime" default:"30m"` // 接続を使い回す上限
+	ConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" yaml:"connMaxIdleTime" default:"5m"` // アイドル状態の接続を閉じるまでの時間
+	ConnectTimeout  time.Duration `env:"DB_CONNECT_TIMEOUT" yaml:"connectTimeout" default:"30s"`    // 起動時の接続を再試行する上限
+	PingTimeout     time.Duration `env:"DB_PING_TIMEOUT" yaml:"pingTimeout" default:"5s"`           // 1回のPingの打ち切り時間
+
+	// クエリの実行方式（cache_statement, cache_describe, describe_exec, exec, simple_protocol）
+	// PgBouncerのトランザクションモードを経由する場合はcache_describe以下にする
+	QueryExecMode          string `env:"DB_QUERY_EXEC_MODE" yaml:"queryExecMode" default:"cache_statement"`
+	StatementCacheCapacity int    `env:"DB_STATEMENT_CACHE_CAPACITY" yaml:"statementCacheCapacity" default:"512"` // 接続ごとにキャッシュするプリペアドステートメントの数
+
+	// 読み込み専用のレプリカ（カンマ区切りの接続先）。参照系のクエリ（Get*, Search*等）をラウンドロビンで振り分ける
+	ReplicaURLs []string `env:"POSTGRES_REPLICA_URLS" yaml:"replicaUrls"`
+	// レプリカの死活監視の間隔（応答しないレプリカには振り分けず、すべて応答しなければプライマリで読む）
+	ReplicaCheckInterval time.Duration `env:"DB_REPLICA_CHECK_INTERVAL" yaml:"replicaCheckInterval" default:"5s"`
+	// 書き込み後にそのクライアントの読み込みをプライマリに固定する時間（レプリカの遅延で自分の変更が見えなくなるのを防ぐ。0で無効）
+	ReplicaStickyWindow time.Duration `env:"DB_REPLICA_STICKY_WINDOW" yaml:"replicaStickyWindow" default:"5s"`
+
+	// 起動時のマイグレーション（複数のインスタンスが同時に起動してもアドバイザリロックで1つずつ適用する）
+	AutoMigrate          bool          `env:"AUTO_MIGRATE" yaml:"autoMigrate" default:"false"`                 // 起動時に未適用のマイグレーションを適用するか
+	MigrationStrict      bool          `env:"MIGRATION_STRICT" yaml:"migrationStrict" default:"false"`         // DBのバージョンが古い・dirtyの場合に起動を中止するか
+	MigrationLockTimeout time.Duration `env:"MIGRATION_LOCK_TIMEOUT" yaml:"migrationLockTimeout" default:"5m"` // 他のインスタンスのマイグレーションの完了を待つ上限
+}
+
+// CacheConfig はDBの読み込みキャッシュ（インスタンスごとのメモリ）の設定
+// マスタ（m_localGovernment）とシステムの参照をキャッシュし、このインスタンスからの書き込みで破棄する
+type CacheConfig struct {
+	Enabled    bool          `env:"CACHE_ENABLED" yaml:"enabled" default:"true"`
+	MasterTTL  time.Duration `env:"CACHE_MASTER_TTL" yaml:"masterTtl" default:"1h"`      // マスタのキャッシュの有効期間
+	SystemTTL  time.Duration `env:"CACHE_SYSTEM_TTL" yaml:"systemTtl" default:"30s"`     // システムのキャッシュの有効期間（他のインスタンスの書き込みが反映されるまでの上限）
+	MaxEntries int           `env:"CACHE_MAX_ENTRIES" yaml:"maxEntries" default:"10000"` // キャッシュごとの最大件数
+	Notify     bool          `env:"CACHE_NOTIFY" yaml:"notify" default:"false"`          // LISTEN/NOTIFYで他のインスタンスの書き込みをすぐに反映するか
+}
+
+// HTTPConfig はHTTPサーバーのタイムアウトとシャットダウンの設定（"30s" のような time.ParseDuration の形式）
+// Cloud RunはSIGTERMから10秒後に強制終了するため、DrainDelayとShutdownTimeoutの合計はそれより短くする
+type HTTPConfig struct {
+	ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" yaml:"readTimeout" default:"30s"`              // リクエスト全体（ボディを含む）の読み込み上限
+	ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" yaml:"readHeaderTimeout" default:"10s"` // リクエストヘッダーの読み込み上限
+	WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" yaml:"writeTimeout" default:"120s"`           // レスポンスの書き込み上限（エクスポートのストリーミングを含む）
+	IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" yaml:"idleTimeout" default:"120s"`             // Keep-Alive接続のアイドル上限
+	DrainDelay        time.Duration `env:"SHUTDOWN_DRAIN_DELAY" yaml:"drainDelay" default:"0s"`             // SIGTERM受信後、ヘルスチェックを失敗させてからリスナーを止めるまでの待ち時間
+	ShutdownTimeout   time.Duration `env:"SHUTDOWN_TIMEOUT" yaml:"shutdownTimeout" default:"8s"`            // 処理中のリクエストの完了を待つ上限
+
+	// リクエストボディの上限（バイト数、0で無制限）。超えた場合は413を返す
+	MaxBodyBytes      int64    `env:"HTTP_MAX_BODY_BYTES" yaml:"maxBodyBytes" default:"1048576"`                                         // 既定の上限（1MB）
+	RouteMaxBodyBytes []string `env:"HTTP_ROUTE_MAX_BODY_BYTES" yaml:"routeMaxBodyBytes" default:"POST /api/v1/systems/import=10485760"` // ルート別の上限（"メソッド ルート定義=バイト数" の形式）
+
+	// クライアントのIPアドレス（レート制限のキー・アクセスログ）の判定。未指定の場合はX-Forwarded-Forを信頼せず接続元のアドレスを使う
+	TrustedProxies  []string `env:"HTTP_TRUSTED_PROXIES" yaml:"trustedProxies"`   // X-Forwarded-Forを信頼するプロキシのIPアドレス・CIDR
+	TrustedPlatform string   `env:"HTTP_TRUSTED_PLATFORM" yaml:"trustedPlatform"` // クライアントのIPアドレスを付けるプラットフォーム（google-app-engine, cloudflare）
+}
+
+// HealthConfig はレディネスチェック（/readyz）の設定
+type HealthConfig struct {
+	CheckTimeout    time.Duration `env:"HEALTH_CHECK_TIMEOUT" yaml:"checkTimeout" default:"2s"`         // 各チェックの打ち切り時間
+	PoolSaturation  float64       `env:"HEALTH_POOL_SATURATION" yaml:"poolSaturation" default:"0.9"`    // 使用中の接続数が最大接続数のこの割合以上で失敗とする
+	CheckMigrations bool          `env:"HEALTH_CHECK_MIGRATIONS" yaml:"checkMigrations" default:"true"` // DBのマイグレーションのバージョンを確認するか
+}
+
+// TracingConfig はOpenTelemetryのトレースの設定
+type TracingConfig struct {
+	Exporter    string  `env:"OTEL_TRACES_EXPORTER" yaml:"exporter" default:"none"`      // none, stdout, otlp
+	Endpoint    string  `env:"OTEL_EXPORTER_OTLP_ENDPOINT" yaml:"endpoint"`              // OTLP（HTTP）の送信先（例: http://localhost:4318）。未指定時はOTLPの既定値
+	SampleRatio float64 `env:"OTEL_TRACES_SAMPLER_RATIO" yaml:"sampleRatio" default:"1"` // 起点となるトレースを記録する割合（0〜1）
+}
+
+// AdminConfig は管理用エンドポイント（/admin/*）の設定
+type AdminConfig struct {
+	Token string `env:"ADMIN_TOKEN" yaml:"token"` // Authorization: Bearer で渡すトークン。未設定の場合、管理用エンドポイントは無効
+}
+
+// RateLimitConfig はAPI（/api/v1/*）のレート制限の設定
+// ポリシーは "回数/期間"（"60/1m" は1分あたり60回）の形式で指定し、認証済みのユーザー（未認証の場合はIPアドレス）ごとに制限する
+type RateLimitConfig struct {
+	Enabled bool     `env:"RATE_LIMIT_ENABLED" yaml:"enabled" default:"true"`
+	Default string   `env:"RATE_LIMIT_DEFAULT" yaml:"default" default:"120/1m"`                                                                            // ルート別のポリシーがないルートに適用するポリシー
+	Routes  []string `env:"RATE_LIMIT_ROUTES" yaml:"routes" default:"GET /api/v1/systems=60/1m,GET /api/v1/search=60/1m,POST /api/v1/systems/import=5/1m"` // ルート別のポリシー（"メソッド ルート定義=回数/期間" の形式。"none" で制限しない）
+}
+
+// CORSConfig はCORSの設定
+// オリジンには "https://*.example.jp" のようにサブドメインのワイルドカードを指定できる（"https://example.jp" 自体は含まないため別に指定する）。
+// "*" はすべてのオリジンを許可し、本番環境・AllowCredentialsとの組み合わせでは使えない。
+type CORSConfig struct {
+	AllowOrigins []string `env:"CORS_ALLOW_ORIGINS" yaml:"allowOrigins" default:"*"`
+	AllowMethods []string `env:"CORS_ALLOW_METHODS" yaml:"allowMethods" default:"GET,POST,PUT,PATCH,DELETE,OPTIONS"`
+	AllowHeaders []string `env:"CORS_ALLOW_HEADERS" yaml:"allowHeaders" default:"Content-Type,Authorization,X-Request-ID,If-None-Match"`
+	// ブラウザのスクリプトから読めるレスポンスヘッダー
+	ExposeHeaders []string `env:"CORS_EXPOSE_HEADERS" yaml:"exposeHeaders" default:"X-Request-ID,ETag,Content-Disposition,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy,Retry-After"`
+	// Cookie・Authorizationヘッダー付きのリクエストを許可するか
+	AllowCredentials bool `env:"CORS_ALLOW_CREDENTIALS" yaml:"allowCredentials" default:"false"`
+	// プリフライトの結果をブラウザがキャッシュする時間
+	MaxAge time.Duration `env:"CORS_MAX_AGE" yaml:"maxAge" default:"12h"`
+}
+
+// CompressionConfig はレスポンス圧縮（brotli・gzip）の設定
+// Accept-Encodingでbrotliを受け付けるクライアントにはbrotli、それ以外でgzipを受け付けるクライアントにはgzipで返す
+type CompressionConfig struct {
+	Enabled bool `env:"COMPRESSION_ENABLED" yaml:"enabled" default:"true"`
+	MinSize int  `env:"COMPRESSION_MIN_SIZE" yaml:"minSize" default:"1024"` // これより小さいレスポンスは圧縮しない（バイト）
+	// 圧縮するContent-Type（XLSXのように圧縮済みの形式は含めない）
+	ContentTypes []string `env:"COMPRESSION_CONTENT_TYPES" yaml:"contentTypes" default:"application/json,application/x-ndjson,text/csv,text/plain"`
+}
+
+// AllowAllOrigins はすべてのオリジンを許可する（"*" が指定されている）かどうか
+func (c CORSConfig) AllowAllOrigins() bool {
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// BodyLimits はRouteMaxBodyBytesを "メソッド ルート定義" をキーにしたマップにする（検証済みの値のみ）
+func (c HTTPConfig) BodyLimits() map[string]int64 {
+	limits := make(map[string]int64, len(c.RouteMaxBodyBytes))
+	for _, entry := range c.RouteMaxBodyBytes {
+		route, size, ok := parseRouteEntry(entry)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseInt(size, 10, 64); err == nil && n >= 0 {
+			limits[route] = n
+		}
+	}
+	return limits
+}
+
+// parseRouteEntry は "メソッド ルート定義=値" の形式の設定値を分割する
+func parseRouteEntry(entry string) (route, value string, ok bool) {
+	route, value, ok = strings.Cut(entry, "=")
+	route, value = strings.TrimSpace(route), strings.TrimSpace(value)
+	method, path, hasPath := strings.Cut(route, " ")
+	if !ok || !hasPath || method == "" || !strings.HasPrefix(path, "/") || value == "" {
+		return "", "", false
+	}
+	return route, value, true
+}
+
+// IsProduction は本番環境かどうか
+func (c AppConfig) IsProduction() bool {
+	return c.Environment == "production"
+}
+
+// IsDevelopment は開発環境かどうか
+func (c AppConfig) IsDevelopment() bool {
+	return c.Environment == "development"
+}
@@ -0,0 +1,460 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnvKey はYAML設定ファイルのパスを指定する環境変数
+const FileEnvKey = "CONFIG_FILE"
+
+// Options は読み込み元の指定
+type Options struct {
+	// EnvFiles は読み込む.envファイル（先に指定したものが優先、存在しないファイルは無視）
+	EnvFiles []string
+	// YAMLFile はYAML設定ファイルのパス。空ならCONFIG_FILE環境変数を参照し、それも空なら読み込まない
+	YAMLFile string
+}
+
+// ValidationError は設定の不足・不正をまとめたエラー
+type ValidationError struct {
+	Missing []string // 未設定の必須キー
+	Invalid []string // 値が不正なキーと理由
+}
+
+func (e *ValidationError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing required keys: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		parts = append(parts, "invalid values: "+strings.Join(e.Invalid, "; "))
+	}
+	return "invalid configuration: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) empty() bool {
+	return len(e.Missing) == 0 && len(e.Invalid) == 0
+}
+
+// Load は設定を読み込んで検証する
+// 不足・不正な項目は1件ずつではなく、すべてをまとめて*ValidationErrorで返す
+func Load(opts Options) (*Config, error) {
+	dotenv, err := readEnvFiles(opts.EnvFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	yamlFile := opts.YAMLFile
+	if yamlFile == "" {
+		yamlFile = lookup(FileEnvKey, dotenv)
+	}
+	values, err := readYAMLFile(yamlFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	verr := &ValidationError{}
+	src := source{dotenv: dotenv, yaml: values}
+	src.fill(reflect.ValueOf(config).Elem(), "", verr)
+	config.validate(verr)
+
+	if !verr.empty() {
+		return nil, verr
+	}
+	return config, nil
+}
+
+// validate は値の組み合わせや列挙値を検証
+func (c *Config) validate(verr *ValidationError) {
+	switch c.App.Environment {
+	case "development", "staging", "production":
+	default:
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("APP_ENV=%q (must be development, staging or production)", c.App.Environment))
+	}
+
+	switch c.App.GinMode {
+	case "debug", "release", "test":
+	default:
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("GIN_MODE=%q (must be debug, release or test)", c.App.GinMode))
+	}
+
+	if c.Health.PoolSaturation <= 0 || c.Health.PoolSaturation > 1 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("HEALTH_POOL_SATURATION=%v (must be greater than 0 and at most 1)", c.Health.PoolSaturation))
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("OTEL_TRACES_EXPORTER=%q (must be none, stdout or otlp)", c.Tracing.Exporter))
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("OTEL_TRACES_SAMPLER_RATIO=%v (must be between 0 and 1)", c.Tracing.SampleRatio))
+	}
+
+	if c.Log.SamplingInitial < 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("LOG_SAMPLING_INITIAL=%d (must be 0 or greater)", c.Log.SamplingInitial))
+	}
+	if c.Log.SamplingThereafter < 1 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("LOG_SAMPLING_THEREAFTER=%d (must be 1 or greater)", c.Log.SamplingThereafter))
+	}
+
+	for _, spec := range c.Log.Levels {
+		name, level, ok := strings.Cut(spec, "=")
+		if !ok || strings.TrimSpace(name) == "" || !validLogLevel(strings.TrimSpace(level)) {
+			verr.Invalid = append(verr.Invalid, fmt.Sprintf("LOG_LEVELS=%q (each entry must be name=level with debug, info, warn or error)", spec))
+		}
+	}
+
+	if c.Database.MaxOpenConns < 1 || c.Database.MinConns < 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("DB_MAX_OPEN_CONNS=%d, DB_MIN_CONNS=%d (must be 1 or greater and 0 or greater)", c.Database.MaxOpenConns, c.Database.MinConns))
+	} else if c.Database.MinConns > c.Database.MaxOpenConns {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("DB_MIN_CONNS=%d (must not exceed DB_MAX_OPEN_CONNS=%d)", c.Database.MinConns, c.Database.MaxOpenConns))
+	}
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("DB_CONN_MAX_LIFETIME=%s, DB_CONN_MAX_IDLE_TIME=%s (must be 0 or greater)", c.Database.ConnMaxLifetime, c.Database.ConnMaxIdleTime))
+	}
+	if c.Database.ConnectTimeout <= 0 || c.Database.PingTimeout <= 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("DB_CONNECT_TIMEOUT=%s, DB_PING_TIMEOUT=%s (must be greater than 0)", c.Database.ConnectTimeout, c.Database.PingTimeout))
+	}
+	if !validQueryExecMode(c.Database.QueryExecMode) {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("DB_QUERY_EXEC_MODE=%q (must be cache_statement, cache_describe, describe_exec, exec or simple_protocol)", c.Database.QueryExecMode))
+	}
+	if c.Database.StatementCacheCapacity < 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("DB_STATEMENT_CACHE_CAPACITY=%d (must be 0 or greater)", c.Database.StatementCacheCapacity))
+	}
+	for _, url := range c.Database.ReplicaURLs {
+		if !strings.HasPrefix(url, "postgres://") && !strings.HasPrefix(url, "postgresql://") {
+			verr.Invalid = append(verr.Invalid, "POSTGRES_REPLICA_URLS (each entry must be a postgres:// URL)")
+			break
+		}
+	}
+	if c.Database.ReplicaCheckInterval <= 0 || c.Database.ReplicaStickyWindow < 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("DB_REPLICA_CHECK_INTERVAL=%s, DB_REPLICA_STICKY_WINDOW=%s (must be greater than 0 and 0 or greater)", c.Database.ReplicaCheckInterval, c.Database.ReplicaStickyWindow))
+	}
+
+	if c.Database.AutoMigrate && c.Database.MigrationLockTimeout <= 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("MIGRATION_LOCK_TIMEOUT=%s (must be greater than 0 when AUTO_MIGRATE is enabled)", c.Database.MigrationLockTimeout))
+	}
+
+	if c.HTTP.MaxBodyBytes < 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("HTTP_MAX_BODY_BYTES=%d (must be 0 or greater)", c.HTTP.MaxBodyBytes))
+	}
+	for _, entry := range c.HTTP.RouteMaxBodyBytes {
+		_, size, ok := parseRouteEntry(entry)
+		if n, err := strconv.ParseInt(size, 10, 64); !ok || err != nil || n < 0 {
+			verr.Invalid = append(verr.Invalid, fmt.Sprintf("HTTP_ROUTE_MAX_BODY_BYTES=%q (each entry must be \"METHOD /route=bytes\")", entry))
+		}
+	}
+
+	for _, proxy := range c.HTTP.TrustedProxies {
+		if !validTrustedProxy(proxy) {
+			verr.Invalid = append(verr.Invalid, fmt.Sprintf("HTTP_TRUSTED_PROXIES=%q (each entry must be an IP address or CIDR)", proxy))
+		}
+	}
+	if !validTrustedPlatform(c.HTTP.TrustedPlatform) {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("HTTP_TRUSTED_PLATFORM=%q (must be google-app-engine or cloudflare)", c.HTTP.TrustedPlatform))
+	}
+
+	if !validRatePolicy(c.RateLimit.Default) {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("RATE_LIMIT_DEFAULT=%q (must be limit/period such as 60/1m)", c.RateLimit.Default))
+	}
+	for _, entry := range c.RateLimit.Routes {
+		_, spec, ok := parseRouteEntry(entry)
+		if !ok || (spec != "none" && !validRatePolicy(spec)) {
+			verr.Invalid = append(verr.Invalid, fmt.Sprintf("RATE_LIMIT_ROUTES=%q (each entry must be \"METHOD /route=limit/period\" or \"METHOD /route=none\")", entry))
+		}
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		verr.Invalid = append(verr.Invalid, "CORS_ALLOW_ORIGINS (at least one origin is required)")
+	}
+	if c.CORS.AllowAllOrigins() {
+		switch {
+		case c.App.IsProduction():
+			verr.Invalid = append(verr.Invalid, "CORS_ALLOW_ORIGINS=\"*\" (not allowed in production; list the allowed origins)")
+		case c.CORS.AllowCredentials:
+			verr.Invalid = append(verr.Invalid, "CORS_ALLOW_ORIGINS=\"*\" (cannot be combined with CORS_ALLOW_CREDENTIALS=true)")
+		case len(c.CORS.AllowOrigins) > 1:
+			verr.Invalid = append(verr.Invalid, "CORS_ALLOW_ORIGINS=\"*\" (must not be combined with other origins)")
+		}
+	} else {
+		for _, origin := range c.CORS.AllowOrigins {
+			if !validCORSOrigin(origin) {
+				verr.Invalid = append(verr.Invalid, fmt.Sprintf("CORS_ALLOW_ORIGINS=%q (must be scheme://host[:port] or scheme://*.domain)", origin))
+			}
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("CORS_MAX_AGE=%s (must be 0 or greater)", c.CORS.MaxAge))
+	}
+
+	if c.Compression.MinSize < 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("COMPRESSION_MIN_SIZE=%d (must be 0 or greater)", c.Compression.MinSize))
+	}
+
+	if c.Cache.MasterTTL <= 0 || c.Cache.SystemTTL <= 0 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("CACHE_MASTER_TTL=%s, CACHE_SYSTEM_TTL=%s (must be greater than 0)", c.Cache.MasterTTL, c.Cache.SystemTTL))
+	}
+	if c.Cache.MaxEntries < 1 {
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("CACHE_MAX_ENTRIES=%d (must be 1 or greater)", c.Cache.MaxEntries))
+	}
+
+	if c.Admin.Token != "" && len(c.Admin.Token) < 16 {
+		verr.Invalid = append(verr.Invalid, "ADMIN_TOKEN (must be at least 16 characters)")
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		verr.Invalid = append(verr.Invalid, fmt.Sprintf("LOG_LEVEL=%q (must be debug, info, warn or error)", c.Log.Level))
+	}
+}
+
+// validLogLevel はLOG_LEVEL・LOG_LEVELSで指定できるレベルかどうか
+func validLogLevel(level string) bool {
+	switch level {
+	case "debug", "info", "warn", "warning", "error":
+		return true
+	}
+	return false
+}
+
+// validQueryExecMode はDB_QUERY_EXEC_MODEで指定できる実行方式かどうか
+func validQueryExecMode(mode string) bool {
+	switch mode {
+	case "cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol":
+		return true
+	}
+	return false
+}
+
+// validTrustedProxy はHTTP_TRUSTED_PROXIESで指定できるIPアドレス・CIDRかどうか
+func validTrustedProxy(proxy string) bool {
+	if _, _, err := net.ParseCIDR(proxy); err == nil {
+		return true
+	}
+	return net.ParseIP(proxy) != nil
+}
+
+// validTrustedPlatform はHTTP_TRUSTED_PLATFORMで指定できるプラットフォームかどうか（空は指定なし）
+func validTrustedPlatform(platform string) bool {
+	switch platform {
+	case "", "google-app-engine", "cloudflare":
+		return true
+	}
+	return false
+}
+
+// validRatePolicy はRATE_LIMIT_DEFAULT・RATE_LIMIT_ROUTESで指定できるポリシー（"60/1m"）かどうか
+func validRatePolicy(spec string) bool {
+	limit, period, ok := strings.Cut(spec, "/")
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 1 {
+		return false
+	}
+	d, err := time.ParseDuration(period)
+	return err == nil && d > 0
+}
+
+// validCORSOrigin はCORS_ALLOW_ORIGINSに指定できるオリジン（"https://app.example.jp" または "https://*.example.jp"）かどうか
+func validCORSOrigin(origin string) bool {
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok || (scheme != "http" && scheme != "https") || host == "" || strings.ContainsAny(host, "/?#") {
+		return false
+	}
+	if strings.Contains(host, "*") {
+		// ワイルドカードは先頭のサブドメインのみ（"*.example.jp"）
+		domain, ok := strings.CutPrefix(host, "*.")
+		return ok && domain != "" && !strings.Contains(domain, "*")
+	}
+	return true
+}
+
+// readEnvFiles は.envファイルを読み込む（プロセスの環境変数は変更しない）
+func readEnvFiles(paths []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, path := range paths {
+		file, err := godotenv.Read(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		for k, v := range file {
+			if _, ok := values[k]; !ok {
+				values[k] = v
+			}
+		}
+	}
+	return values, nil
+}
+
+// readYAMLFile はYAMLファイルを "section.key" をキーとする平坦なmapとして読み込む
+func readYAMLFile(path string) (map[string]string, error) {
+	values := make(map[string]string)
+	if path == "" {
+		return values, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	flatten("", doc, values)
+	return values, nil
+}
+
+func flatten(prefix string, node map[string]interface{}, out map[string]string) {
+	for k, v := range node {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			flatten(key, value, out)
+		case []interface{}:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			out[key] = strings.Join(items, ",")
+		case nil:
+		default:
+			out[key] = fmt.Sprint(value)
+		}
+	}
+}
+
+func lookup(key string, dotenv map[string]string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return dotenv[key]
+}
+
+// source は各フィールドの値の読み込み元
+type source struct {
+	dotenv map[string]string
+	yaml   map[string]string
+}
+
+// fill はタグに従って構造体の各フィールドに値を設定する
+func (s source) fill(v reflect.Value, yamlPrefix string, verr *ValidationError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		yamlKey := field.Tag.Get("yaml")
+		if yamlPrefix != "" {
+			yamlKey = yamlPrefix + "." + yamlKey
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
+			s.fill(v.Field(i), yamlKey, verr)
+			continue
+		}
+
+		envKey := field.Tag.Get("env")
+		name := fmt.Sprintf("%s (%s)", envKey, yamlKey)
+
+		raw, ok := s.value(envKey, yamlKey)
+		if !ok {
+			if field.Tag.Get("required") == "true" {
+				verr.Missing = append(verr.Missing, name)
+				continue
+			}
+			raw = field.Tag.Get("default")
+		}
+
+		if err := setValue(v.Field(i), raw); err != nil {
+			verr.Invalid = append(verr.Invalid, fmt.Sprintf("%s=%q (%v)", name, raw, err))
+		}
+	}
+}
+
+func (s source) value(envKey, yamlKey string) (string, bool) {
+	if v, ok := os.LookupEnv(envKey); ok && v != "" {
+		return v, true
+	}
+	if v, ok := s.dotenv[envKey]; ok && v != "" {
+		return v, true
+	}
+	if v, ok := s.yaml[yamlKey]; ok && v != "" {
+		return v, true
+	}
+	return "", false
+}
+
+// setValue は文字列をフィールドの型に変換して設定する
+func setValue(field reflect.Value, raw string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.New("must be a duration such as 30s")
+		}
+		if d < 0 {
+			return errors.New("must not be negative")
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
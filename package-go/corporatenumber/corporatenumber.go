@@ -0,0 +1,74 @@
+// Package corporatenumber は国税庁が指定する13桁の法人番号を扱う
+package corporatenumber
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Length は法人番号の桁数（先頭1桁のチェックデジット＋12桁の基礎番号）
+const Length = 13
+
+var (
+	// ErrInvalidFormat は13桁の数字でない場合のエラー
+	ErrInvalidFormat = errors.New("corporate number must be 13 digits")
+	// ErrInvalidCheckDigit はチェックデジットが一致しない場合のエラー
+	ErrInvalidCheckDigit = errors.New("corporate number has an invalid check digit")
+)
+
+// Normalize は全角数字を半角にし、区切りのハイフンや空白を取り除く
+func Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '\t':
+			return -1
+		}
+		return r
+	}, norm.NFKC.String(strings.TrimSpace(s)))
+}
+
+// CheckDigit は12桁の基礎番号からチェックデジットを計算する
+// 基礎番号の最下位の桁を1番目として、奇数番目の桁に1、偶数番目の桁に2を掛けた総和を9で割った余りを9から引いた値。
+func CheckDigit(base string) (int, error) {
+	if len(base) != Length-1 || !isDigits(base) {
+		return 0, ErrInvalidFormat
+	}
+
+	sum := 0
+	for n := 1; n <= Length-1; n++ {
+		digit := int(base[len(base)-n] - '0')
+		if n%2 == 0 {
+			sum += digit * 2
+		} else {
+			sum += digit
+		}
+	}
+	return 9 - sum%9, nil
+}
+
+// Validate は正規化済みの法人番号の桁数とチェックデジットを検証する
+func Validate(number string) error {
+	if len(number) != Length || !isDigits(number) {
+		return ErrInvalidFormat
+	}
+
+	want, err := CheckDigit(number[1:])
+	if err != nil {
+		return err
+	}
+	if int(number[0]-'0') != want {
+		return ErrInvalidCheckDigit
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"sample-micro-service-api/package-go/cache"
+	"sample-micro-service-api/package-go/config"
+)
+
+// キャッシュを破棄する単位（テーブル名。LISTEN/NOTIFYのペイロードと同じ）
+const (
+	TableSystem               = "system"
+	TableLocalGovernment      = "m_localGovernment"
+	TableOrganizationCategory = "m_organizationCategory"
+	TableUserRole             = "m_userRole"
+)
+
+// CachedQuerier はsqlcのQuerierのうち、マスタとシステムの参照をメモリにキャッシュする
+// システムの書き込み（作成・更新・削除）ではシステムのキャッシュをすべて破棄する。
+// ここで上書きしていないクエリ（検索・ベンダー等）はそのままDBに問い合わせる。
+// 読み込みをプライマリに固定したリクエストはキャッシュを使わない（WithPrimary）。
+type CachedQuerier struct {
+	Querier
+	master  *cache.Cache
+	systems *cache.Cache
+}
+
+// NewCachedQuerier はinnerの読み込みをキャッシュするCachedQuerierを作成
+func NewCachedQuerier(inner Querier, cfg config.CacheConfig) *CachedQuerier {
+	return &CachedQuerier{
+		Querier: inner,
+		master:  cache.New(cfg.MasterTTL, cfg.MaxEntries),
+		systems: cache.New(cfg.SystemTTL, cfg.MaxEntries),
+	}
+}
+
+// Caches は名前ごとのキャッシュ（メトリクスの登録用）
+func (q *CachedQuerier) Caches() map[string]*cache.Cache {
+	return map[string]*cache.Cache{
+		"master":  q.master,
+		"systems": q.systems,
+	}
+}
+
+// Invalidate はtableのキャッシュを破棄する（不明なテーブルの場合はすべて破棄する）
+func (q *CachedQuerier) Invalidate(table string) {
+	switch table {
+	case TableSystem:
+		q.systems.Purge()
+	case TableLocalGovernment, TableOrganizationCategory, TableUserRole:
+		q.master.Purge()
+	default:
+		q.master.Purge()
+		q.systems.Purge()
+	}
+}
+
+// --- マスタ ---
+
+func (q *CachedQuerier) GetLocalGovernment(ctx context.Context, id string) (MLocalGovernment, error) {
+	return load(ctx, q.master, "GetLocalGovernment|"+id, func(ctx context.Context) (MLocalGovernment, error) {
+		return q.Querier.GetLocalGovernment(ctx, id)
+	})
+}
+
+func (q *CachedQuerier) FindLocalGovernmentsByName(ctx context.Context, arg FindLocalGovernmentsByNameParams) ([]MLocalGovernment, error) {
+	key := fmt.Sprintf("FindLocalGovernmentsByName|%q|%q", arg.PrefectureName, arg.CityName)
+	return loadSlice(ctx, q.master, key, func(ctx context.Context) ([]MLocalGovernment, error) {
+		return q.Querier.FindLocalGovernmentsByName(ctx, arg)
+	})
+}
+
+func (q *CachedQuerier) GetUserRole(ctx context.Context, id int32) (MUserRole, error) {
+	return load(ctx, q.master, fmt.Sprintf("GetUserRole|%d", id), func(ctx context.Context) (MUserRole, error) {
+		return q.Querier.GetUserRole(ctx, id)
+	})
+}
+
+func (q *CachedQuerier) ListUserRoles(ctx context.Context) ([]MUserRole, error) {
+	return loadSlice(ctx, q.master, "ListUserRoles", q.Querier.ListUserRoles)
+}
+
+func (q *CachedQuerier) GetOrganizationCategory(ctx context.Context, id int32) (MOrganizationCategory, error) {
+	return load(ctx, q.master, fmt.Sprintf("GetOrganizationCategory|%d", id), func(ctx context.Context) (MOrganizationCategory, error) {
+		return q.Querier.GetOrganizationCategory(ctx, id)
+	})
+}
+
+func (q *CachedQuerier) ListOrganizationCategories(ctx context.Context) ([]MOrganizationCategory, error) {
+	return loadSlice(ctx, q.master, "ListOrganizationCategories", q.Querier.ListOrganizationCategories)
+}
+
+// --- システムの参照 ---
+
+func (q *CachedQuerier) GetSystem(ctx context.Context, id uuid.UUID) (System, error) {
+	return load(ctx, q.systems, "GetSystem|"+id.String(), func(ctx context.Context) (System, error) {
+		return q.Querier.GetSystem(ctx, id)
+	})
+}
+
+func (q *CachedQuerier) GetSystemByName(ctx context.Context, systemname string) (System, error) {
+	return load(ctx, q.systems, fmt.Sprintf("GetSystemByName|%q", systemname), func(ctx context.Context) (System, error) {
+		return q.Querier.GetSystemByName(ctx, systemname)
+	})
+}
+
+func (q *CachedQuerier) GetSystems(ctx context.Context) ([]System, error) {
+	return loadSlice(ctx, q.systems, "GetSystems", q.Querier.GetSystems)
+}
+
+func (q *CachedQuerier) GetSystemsByEmail(ctx context.Context, mailaddress string) ([]System, error) {
+	return loadSlice(ctx, q.systems, fmt.Sprintf("GetSystemsByEmail|%q", mailaddress), func(ctx context.Context) ([]System, error) {
+		return q.Querier.GetSystemsByEmail(ctx, mailaddress)
+	})
+}
+
+func (q *CachedQuerier) GetSystemsByLocalGovernment(ctx context.Context, localgovernmentid *string) ([]System, error) {
+	key := "GetSystemsByLocalGovernment|null"
+	if localgovernmentid != nil {
+		key = fmt.Sprintf("GetSystemsByLocalGovernment|%q", *localgovernmentid)
+	}
+	return loadSlice(ctx, q.systems, key, func(ctx context.Context) ([]System, error) {
+		return q.Querier.GetSystemsByLocalGovernment(ctx, localgovernmentid)
+	})
+}
+
+func (q *CachedQuerier) SearchSystems(ctx context.Context, arg SearchSystemsParams) ([]System, error) {
+	key := fmt.Sprintf("SearchSystems|%q|%q|%q", arg.Column1, arg.Column2, arg.Column3)
+	return loadSlice(ctx, q.systems, key, func(ctx context.Context) ([]System, error) {
+		return q.Querier.SearchSystems(ctx, arg)
+	})
+}
+
+// --- システムの書き込み（失敗した場合も反映済みの可能性があるため破棄する） ---
+
+func (q *CachedQuerier) CreateSystem(ctx context.Context, arg CreateSystemParams) (System, error) {
+	defer q.systems.Purge()
+	return q.Querier.CreateSystem(ctx, arg)
+}
+
+func (q *CachedQuerier) UpdateSystem(ctx context.Context, arg UpdateSystemParams) (System, error) {
+	defer q.systems.Purge()
+	return q.Querier.UpdateSystem(ctx, arg)
+}
+
+func (q *CachedQuerier) UpdateSystemContact(ctx context.Context, arg UpdateSystemContactParams) (System, error) {
+	defer q.systems.Purge()
+	return q.Querier.UpdateSystemContact(ctx, arg)
+}
+
+func (q *CachedQuerier) CopySystems(ctx context.Context, arg []CopySystemsParams) (int64, error) {
+	defer q.systems.Purge()
+	return q.Querier.CopySystems(ctx, arg)
+}
+
+func (q *CachedQuerier) DeleteSystem(ctx context.Context, id uuid.UUID) error {
+	defer q.systems.Purge()
+	return q.Querier.DeleteSystem(ctx, id)
+}
+
+// load はkeyのキャッシュを返し、なければfillで読み込んで保存する
+// 読み込みをプライマリに固定したリクエスト（書き込みの直後など）はキャッシュを使わずにプライマリから読む。
+// キャッシュにはレプリカの遅延中に読んだ古い値が残ることがあり、自分の変更が見えなくなるため。
+func load[T any](ctx context.Context, c *cache.Cache, key string, fill func(ctx context.Context) (T, error)) (T, error) {
+	if UsesPrimary(ctx) {
+		return fill(ctx)
+	}
+	return cache.Load(ctx, c, key, fill)
+}
+
+// loadSlice はキャッシュしたスライスを呼び出し元が変更しても影響しないよう複製して返す
+func loadSlice[T any](ctx context.Context, c *cache.Cache, key string, fill func(ctx context.Context) ([]T, error)) ([]T, error) {
+	rows, err := load(ctx, c, key, fill)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(rows), nil
+}
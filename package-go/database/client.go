@@ -1,56 +1,210 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"os"
+	"math/rand/v2"
+	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
 
+	"sample-micro-service-api/package-go/config"
 	"sample-micro-service-api/package-go/database/internal/db"
+	"sample-micro-service-api/package-go/logging"
 )
 
 type Client struct {
-	DB      *sql.DB
-	Queries *db.Queries
+	// Pool はpgxの接続プール
+	Pool *pgxpool.Pool
+	// DB はPoolをdatabase/sqlとして使うアダプタ（golang-migrate等、database/sqlを前提とするライブラリ用）
+	DB *sql.DB
+	// Queries はsqlcのクエリ（EnableCache後は読み込みをキャッシュするCachedQuerier）
+	Queries Querier
+	// Conn はフックを通してDBにアクセスするDBTX（sqlcを使わない動的SQL用）
+	// クエリの先頭に "-- name: X" を付けるとフックにクエリ名として渡される
+	Conn DBTX
+
+	cache       *CachedQuerier
+	replicas    *replicaSet // POSTGRES_REPLICA_URLSが未設定の場合はnil
+	hooks       []QueryHook // トランザクション内のクエリにも同じフックを適用する
+	pingTimeout time.Duration
 }
 
 // NewClient creates a new database client
-func NewClient() (*Client, error) {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		// .envファイルが見つからない場合は警告のみ
+// hooks are run around every sqlc query executed through Queries (metrics, tracing, ...)
+// POSTGRES_REPLICA_URLSが設定されている場合、QueriesとConnの参照系のクエリをレプリカに振り分ける
+func NewClient(cfg config.DatabaseConfig, hooks ...QueryHook) (*Client, error) {
+	pool, err := Open(context.Background(), cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	databaseURL := os.Getenv("POSTGRES_URL")
-	if databaseURL == "" {
-		return nil, fmt.Errorf("POSTGRES_URL environment variable is required")
+	var replicas *replicaSet
+	var target DBTX = pool
+	if len(cfg.ReplicaURLs) > 0 {
+		replicas, err = openReplicas(cfg)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		target = &routingDBTX{primary: pool, replicas: replicas}
 	}
 
-	database, err := sql.Open("postgres", databaseURL)
+	conn := WithQueryHooks(target, hooks...)
+	return &Client{
+		Pool:        pool,
+		DB:          stdlib.OpenDBFromPool(pool),
+		Queries:     db.New(conn),
+		Conn:        conn,
+		replicas:    replicas,
+		hooks:       hooks,
+		pingTimeout: cfg.PingTimeout,
+	}, nil
+}
+
+// 起動時の接続の再試行の間隔（指数的に伸ばし、maxConnectBackoffで頭打ちにする）
+const (
+	initialConnectBackoff = 250 * time.Millisecond
+	maxConnectBackoff     = 5 * time.Second
+)
+
+// queryExecModes はDB_QUERY_EXEC_MODEの値とpgxの実行方式の対応
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// Open はPostgresに接続し、接続プールを作成する
+// 起動直後にDBがまだ接続を受け付けていない場合に備え、ConnectTimeoutまで指数バックオフでPingを再試行する
+func Open(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	pool, err := newPool(ctx, cfg, cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+
+	logger := logging.Named(logging.GetLogger(), logging.LoggerDatabase)
+	backoff := initialConnectBackoff
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx, pool, cfg.PingTimeout)
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("Connected to database", zap.Int("attempts", attempt))
+			}
+			return pool, nil
+		}
+		if permanentConnectError(err) {
+			pool.Close()
+			return nil, fmt.Errorf("failed to ping database: %w", err)
+		}
+
+		// 同時に起動したインスタンスが一斉に再接続しないよう、待ち時間を backoff/2〜backoff の間でばらつかせる
+		wait := backoff/2 + rand.N(backoff/2+1)
+		logger.Warn("Database is not ready, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("retryIn", wait),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			pool.Close()
+			return nil, fmt.Errorf("failed to ping database after %d attempts within %s: %w", attempt, cfg.ConnectTimeout, err)
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
+
+// newPool はurlへの接続プールをcfgの設定で作成する（接続は最初に使われた時点で開く）
+func newPool(ctx context.Context, cfg config.DatabaseConfig, url string) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database url: %w", err)
+	}
+	poolConfig.MaxConns = int32(cfg.MaxOpenConns)
+	poolConfig.MinConns = int32(cfg.MinConns)
+	poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+	poolConfig.MaxConnIdleTime = cfg.ConnMaxIdleTime
+	if mode, ok := queryExecModes[cfg.QueryExecMode]; ok {
+		poolConfig.ConnConfig.DefaultQueryExecMode = mode
+	}
+	poolConfig.ConnConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+	poolConfig.ConnConfig.DescriptionCacheCapacity = cfg.StatementCacheCapacity
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	return pool, nil
+}
 
-	// Test connection
-	if err := database.Ping(); err != nil {
-		database.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+// permanentConnectError は再試行しても解決しない接続エラー（認証の失敗）かどうか
+func permanentConnectError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
 	}
+	return strings.HasPrefix(pgErr.Code, "28")
+}
 
-	return &Client{
-		DB:      database,
-		Queries: db.New(database),
-	}, nil
+// ping はtimeoutで打ち切るPing
+func ping(ctx context.Context, pool *pgxpool.Pool, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return pool.Ping(ctx)
+}
+
+// EnableCache はQueriesの読み込みをメモリにキャッシュするCachedQuerierに置き換える
+func (c *Client) EnableCache(cfg config.CacheConfig) *CachedQuerier {
+	c.cache = NewCachedQuerier(c.Queries, cfg)
+	c.Queries = c.cache
+	return c.cache
+}
+
+// InvalidateCache はQueriesを通さずに書き込んだ（一括取り込み等）テーブルのキャッシュを破棄する
+// キャッシュが無効な場合は何もしない
+func (c *Client) InvalidateCache(tables ...string) {
+	if c.cache == nil {
+		return
+	}
+	for _, table := range tables {
+		c.cache.Invalidate(table)
+	}
+}
+
+// ReplicaPools はレプリカの名前ごとの接続プール（メトリクスの登録用。レプリカがなければ空）
+func (c *Client) ReplicaPools() map[string]*pgxpool.Pool {
+	if c.replicas == nil {
+		return nil
+	}
+	return c.replicas.pools()
 }
 
 // Close closes the database connection
 func (c *Client) Close() error {
-	return c.DB.Close()
+	if c.replicas != nil {
+		c.replicas.close()
+	}
+	err := c.DB.Close()
+	c.Pool.Close()
+	return err
 }
 
 // IsConnected checks if the database connection is alive
-func (c *Client) IsConnected() bool {
-	return c.DB.Ping() == nil
+// DB_PING_TIMEOUT（ctxの期限が短ければその期限）で打ち切る
+func (c *Client) IsConnected(ctx context.Context) bool {
+	return ping(ctx, c.Pool, c.pingTimeout) == nil
 } 
\ No newline at end of file
@@ -1,151 +1,205 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
+	"sample-micro-service-api/package-go/config"
+	appdb "sample-micro-service-api/package-go/database"
+	"sample-micro-service-api/package-go/database/importer"
+	"sample-micro-service-api/package-go/database/nta"
 	"sample-micro-service-api/package-go/database/seed"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func main() {
-	// Load environment variables from parent directory
-	if err := godotenv.Load("../.env"); err != nil {
-		if err := godotenv.Load("../../.env"); err != nil {
-			log.Printf("Warning: .env file not found: %v", err)
-		}
-	}
-
-	// Define CLI commands
+	// Define CLI commands（マイグレーションはサブコマンド、それ以外はフラグで指定する）
 	var (
-		migrateUp    = flag.Bool("migrate-up", false, "Run database migrations up")
-		migrateDown  = flag.Bool("migrate-down", false, "Run database migrations down")
-		migrateReset = flag.Bool("migrate-reset", false, "Reset database (down then up)")
-		testDB       = flag.Bool("test-db", false, "Test database connection")
-		seedDB       = flag.Bool("seed-db", false, "Seed database with sample data")
+		testDB        = flag.Bool("test-db", false, "Test database connection")
+		seedDB        = flag.Bool("seed-db", false, "Seed database with sample data")
+		importFile    = flag.String("import-systems", "", "Import systems from a CSV/XLSX file")
+		dryRun        = flag.Bool("dry-run", false, "Validate the import file without writing to the database")
+		encoding      = flag.String("encoding", "auto", "CSV encoding for -import-systems and -import-nta (auto, utf-8, shift_jis)")
+		ntaFile       = flag.String("import-nta", "", "Enrich vendors from an NTA corporate number file (CSV or ZIP)")
+		ntaAll        = flag.Bool("nta-all", false, "Import every corporation in the NTA file, not only referenced vendors")
+		lockTimeout   = flag.Duration("lock-timeout", 5*time.Minute, "How long a migration waits for another running migration to finish")
+		emitMigration = flag.Bool("emit-migration", false, "With verify, write a migration that corrects the schema drift to MIGRATION_DIR")
 	)
 	flag.Parse()
 
-	databaseURL := os.Getenv("POSTGRES_URL")
-	if databaseURL == "" {
-		log.Fatal("POSTGRES_URL environment variable is required")
+	var command string
+	if flag.NArg() > 0 {
+		command = flag.Arg(0)
+		if !migrationCommands[command] {
+			log.Fatalf("Unknown command %q (run without arguments for usage)", command)
+		}
 	}
 
-	// Connect to database
-	database, err := sql.Open("postgres", databaseURL)
+	// 環境変数と親ディレクトリの.envから設定を読み込む
+	cfg, err := config.Load(config.Options{EnvFiles: []string{"../.env", "../../.env"}})
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
-	defer database.Close()
 
-	// Test database connection
-	if err := database.Ping(); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+	// createはDBに接続せずにMIGRATION_DIRにファイルを作成する
+	if command == "create" {
+		if err := createMigration(cfg.Database.MigrationDir, flag.Args()[1:]); err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		return
 	}
 
-	switch {
-	case *migrateUp:
-		if err := runMigrationsUp(database); err != nil {
-			log.Fatalf("Failed to run migrations up: %v", err)
-		}
-		fmt.Println("Migrations up completed successfully")
+	// Connect to database (DB_CONNECT_TIMEOUTまで再試行する)
+	client, err := appdb.NewClient(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer client.Close()
 
-	case *migrateDown:
-		if err := runMigrationsDown(database); err != nil {
-			log.Fatalf("Failed to run migrations down: %v", err)
+	switch {
+	case command == "verify":
+		if err := verifySchema(client, cfg.Database.MigrationDir, *emitMigration); err != nil {
+			log.Fatalf("Schema verification failed: %v", err)
 		}
-		fmt.Println("Migrations down completed successfully")
 
-	case *migrateReset:
-		if err := runMigrationsDown(database); err != nil {
-			log.Printf("Warning: migrations down failed: %v", err)
-		}
-		if err := runMigrationsUp(database); err != nil {
-			log.Fatalf("Failed to run migrations up: %v", err)
+	case command != "":
+		if err := runMigration(client, command, flag.Args()[1:], *lockTimeout); err != nil {
+			log.Fatalf("Migration %s failed: %v", command, err)
 		}
-		fmt.Println("Database reset completed successfully")
 
 	case *testDB:
 		fmt.Println("Database connection successful!")
 
 	case *seedDB:
-		if err := seedDatabase(database); err != nil {
+		if err := seedDatabase(client.Pool); err != nil {
 			log.Fatalf("Failed to seed database: %v", err)
 		}
 		fmt.Println("Database seeding completed successfully")
 
+	case *importFile != "":
+		if err := importSystems(client, *importFile, *encoding, *dryRun); err != nil {
+			log.Fatalf("Failed to import systems: %v", err)
+		}
+
+	case *ntaFile != "":
+		if err := importNTA(client.Pool, *ntaFile, *encoding, *ntaAll, *dryRun); err != nil {
+			log.Fatalf("Failed to import NTA corporate numbers: %v", err)
+		}
+
 	default:
 		fmt.Println("Database Utility Tool")
 		fmt.Println("Usage:")
-		fmt.Println("  -migrate-up    Run migrations up")
-		fmt.Println("  -migrate-down  Run migrations down") 
-		fmt.Println("  -migrate-reset Reset database (down then up)")
+		fmt.Println("  up             Run all pending migrations")
+		fmt.Println("  down <N|all>   Revert the last N migrations (or all of them)")
+		fmt.Println("  goto <version> Migrate up or down to the given version")
+		fmt.Println("  force <version>")
+		fmt.Println("                 Set the version without running migrations and clear the dirty flag (-1 for none)")
+		fmt.Println("  status         Show the applied version and pending migrations")
+		fmt.Println("  create <name>  Create empty up/down migration files in MIGRATION_DIR")
+		fmt.Println("  verify         Compare the schema with the migrations (tables, columns, indexes, foreign keys)")
+		fmt.Println("                 -emit-migration writes a migration that corrects the drift to MIGRATION_DIR")
+		fmt.Println("                 up/down/goto/force wait up to -lock-timeout for a concurrent migration (flags go before the command)")
 		fmt.Println("  -test-db       Test database connection")
 		fmt.Println("  -seed-db       Seed database with sample data")
+		fmt.Println("  -import-systems <file> [-dry-run] [-encoding auto|utf-8|shift_jis]")
+		fmt.Println("                 Import systems from a CSV/XLSX file")
+		fmt.Println("  -import-nta <file> [-nta-all] [-dry-run] [-encoding auto|utf-8|shift_jis]")
+		fmt.Println("                 Enrich vendors from an NTA corporate number file (CSV or ZIP)")
 	}
 }
 
-func runMigrationsUp(database *sql.DB) error {
-	driver, err := postgres.WithInstance(database, &postgres.Config{})
+func seedDatabase(database *pgxpool.Pool) error {
+	fmt.Println("Starting database seeding...")
+	
+	// Seed systems data
+	if err := seed.SeedSystems(database); err != nil {
+		return fmt.Errorf("failed to seed systems: %w", err)
+	}
+	
+	fmt.Println("All seeding completed successfully!")
+	return nil
+}
+
+func importSystems(client *appdb.Client, path, encoding string, dryRun bool) error {
+	format, err := importer.DetectFormat(path)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate driver: %w", err)
+		return err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://./migrations",
-		"postgres",
-		driver,
-	)
+	enc, err := importer.ParseEncoding(encoding)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	rows, err := importer.Parse(file, format, enc)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return err
 	}
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	report, err := importer.New(client).Import(context.Background(), rows, importer.Options{DryRun: dryRun})
+	if err != nil {
+		return err
 	}
 
+	for _, row := range report.Rows {
+		fmt.Printf("line %d: %-6s %s\n", row.Line, row.Action, row.SystemName)
+		for _, fieldErr := range row.Errors {
+			fmt.Printf("          %s: %s\n", fieldErr.Field, fieldErr.Message)
+		}
+	}
+	fmt.Printf("Total: %d, create: %d, update: %d, error: %d\n",
+		report.Total, report.Creates, report.Updates, report.Errors)
+
+	switch {
+	case report.DryRun:
+		fmt.Println("Dry run: no changes were written")
+	case report.Committed:
+		fmt.Println("Import committed successfully")
+	default:
+		return fmt.Errorf("import aborted: %d rows have errors", report.Errors)
+	}
 	return nil
 }
 
-func runMigrationsDown(database *sql.DB) error {
-	driver, err := postgres.WithInstance(database, &postgres.Config{})
+func importNTA(database *pgxpool.Pool, path, encoding string, all, dryRun bool) error {
+	enc, err := importer.ParseEncoding(encoding)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate driver: %w", err)
+		return err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://./migrations",
-		"postgres",
-		driver,
-	)
+	reader, err := nta.Open(path, enc)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return err
 	}
+	defer reader.Close()
 
-	if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations down: %w", err)
+	report, err := nta.NewEnricher(database).Enrich(context.Background(), reader, nta.Options{All: all, DryRun: dryRun})
+	if err != nil {
+		return err
 	}
 
-	return nil
-}
+	for _, invalid := range report.Invalids {
+		fmt.Printf("line %d: %s: %v\n", invalid.Line, invalid.CorporateNumber, invalid.Err)
+	}
+	fmt.Printf("Read: %d, upsert: %d, skipped: %d, invalid: %d\n",
+		report.Read, report.Upserted, report.Skipped, report.Invalid)
 
-func seedDatabase(database *sql.DB) error {
-	fmt.Println("Starting database seeding...")
-	
-	// Seed systems data
-	if err := seed.SeedSystems(database); err != nil {
-		return fmt.Errorf("failed to seed systems: %w", err)
+	if report.DryRun {
+		fmt.Println("Dry run: no changes were written")
+	} else {
+		fmt.Println("Vendors enriched successfully")
 	}
-	
-	fmt.Println("All seeding completed successfully!")
 	return nil
 }
 
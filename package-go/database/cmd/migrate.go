@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	appdb "sample-micro-service-api/package-go/database"
+)
+
+// migrationCommands はマイグレーションのサブコマンド（create以外はDBに接続して実行する）
+var migrationCommands = map[string]bool{
+	"up": true, "down": true, "goto": true, "force": true, "status": true, "create": true, "verify": true,
+}
+
+// correctiveMigrationHeader は verify -emit-migration で作成するマイグレーションの先頭のコメント
+const correctiveMigrationHeader = `--
+-- スキーマの差分の補正（go run ./cmd -emit-migration verify で生成）
+--
+-- マイグレーションから作ったスキーマにDBを合わせる。テーブル・カラムの削除はデータを失うためコメントアウトしている。
+-- drizzleなどで追加したものを残す場合は、削除の代わりに CREATE ... IF NOT EXISTS などでマイグレーションに取り込む。
+-- 内容を確認してから適用すること。
+--
+
+`
+
+// correctiveMigrationDown は補正マイグレーションのdownの内容（巻き戻しは自動で生成しない）
+const correctiveMigrationDown = "-- 補正の巻き戻しは自動で生成しないため、必要な場合は手で書く\n"
+
+// createMigration は migrationDir に次のバージョンの空のマイグレーションファイルを作成する
+func createMigration(migrationDir string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: create <name>")
+	}
+	paths, err := appdb.CreateMigration(migrationDir, args[0], "", "")
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		fmt.Printf("Created %s\n", path)
+	}
+	return nil
+}
+
+// runMigration はマイグレーションのサブコマンドを実行する
+// status以外はアドバイザリロックを取ってから実行し、同時にデプロイした別のプロセスがロックを持っている場合はlockTimeoutまで待つ
+func runMigration(client *appdb.Client, command string, args []string, lockTimeout time.Duration) error {
+	run, err := migrationFunc(command, args)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := appdb.NewMigrator(client.DB)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if command == "status" {
+		return run(migrator)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	unlock, err := migrator.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}()
+
+	return run(migrator)
+}
+
+// migrationFunc はサブコマンドの引数を検証し、実行する関数を返す（ロックを取る前に使い方の誤りを返すため）
+func migrationFunc(command string, args []string) (func(m *appdb.Migrator) error, error) {
+	switch command {
+	case "up":
+		if len(args) != 0 {
+			return nil, fmt.Errorf("usage: up")
+		}
+		return func(m *appdb.Migrator) error {
+			if err := m.Up(); err != nil {
+				return err
+			}
+			return printStatus(m)
+		}, nil
+
+	case "down":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("usage: down <N|all>")
+		}
+		if args[0] == "all" {
+			return func(m *appdb.Migrator) error {
+				if err := m.Down(); err != nil {
+					return err
+				}
+				return printStatus(m)
+			}, nil
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("down: N must be a positive number or \"all\", got %q", args[0])
+		}
+		return func(m *appdb.Migrator) error {
+			if err := m.Steps(-n); err != nil {
+				return err
+			}
+			return printStatus(m)
+		}, nil
+
+	case "goto":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("usage: goto <version>")
+		}
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("goto: version must be a positive number, got %q", args[0])
+		}
+		return func(m *appdb.Migrator) error {
+			if err := m.Goto(uint(version)); err != nil {
+				return err
+			}
+			return printStatus(m)
+		}, nil
+
+	case "force":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("usage: force <version>")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < -1 {
+			return nil, fmt.Errorf("force: version must be a number (-1 for no version), got %q", args[0])
+		}
+		return func(m *appdb.Migrator) error {
+			if err := m.Force(version); err != nil {
+				return err
+			}
+			return printStatus(m)
+		}, nil
+
+	case "status":
+		if len(args) != 0 {
+			return nil, fmt.Errorf("usage: status")
+		}
+		return printStatus, nil
+	}
+	return nil, fmt.Errorf("unknown migration command %q", command)
+}
+
+// printStatus は適用済みのバージョンと未適用のマイグレーションを表示する
+// dirtyの場合は対処方法を表示し、エラーを返す
+func printStatus(m *appdb.Migrator) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Version: %d (latest: %d)\n", status.Version, status.Latest)
+	if len(status.Pending) == 0 {
+		fmt.Println("Pending: none")
+	} else {
+		pending := make([]string, len(status.Pending))
+		for i, version := range status.Pending {
+			pending[i] = strconv.FormatUint(uint64(version), 10)
+		}
+		fmt.Printf("Pending: %s\n", strings.Join(pending, ", "))
+	}
+
+	if status.Dirty {
+		return fmt.Errorf("database is dirty at version %d; fix the schema by hand, then run `force <version>`", status.Version)
+	}
+	return nil
+}
+
+// verifySchema はDBのスキーマと、適用済みのバージョンまでマイグレーションを適用したスキーマとの差分を表示する
+// 差分がある場合はエラーを返す。emitの場合は差分を補正するマイグレーションをmigrationDirに作成する
+func verifySchema(client *appdb.Client, migrationDir string, emit bool) error {
+	result, err := client.VerifySchema(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Version: %d (latest: %d)\n", result.Version, result.Latest)
+	if result.Dirty {
+		fmt.Printf("Warning: database is dirty at version %d; differences may come from the failed migration\n", result.Version)
+	}
+	if len(result.Drifts) == 0 {
+		fmt.Println("No schema drift found")
+		return nil
+	}
+
+	fmt.Printf("Schema drift (%d):\n", len(result.Drifts))
+	for _, drift := range result.Drifts {
+		fmt.Printf("  %s\n", drift)
+	}
+
+	if emit {
+		// 未適用のマイグレーションより後に補正を置くと、適用済みのバージョンで比べた差分と前提がずれるため
+		if result.Version != result.Latest || result.Dirty {
+			return fmt.Errorf("database must be at the latest version %d and not dirty to emit a corrective migration; run `up` first", result.Latest)
+		}
+		paths, err := appdb.CreateMigration(migrationDir, "fix_schema_drift", correctiveMigrationHeader+appdb.CorrectiveSQL(result.Drifts), correctiveMigrationDown)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			fmt.Printf("Created %s\n", path)
+		}
+	}
+	return fmt.Errorf("%d schema differences found", len(result.Drifts))
+}
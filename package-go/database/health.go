@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"sample-micro-service-api/package-go/health"
+)
+
+// PingChecker はDBへのPingが応答するかを確認するチェック
+func (c *Client) PingChecker() health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		if err := c.Pool.Ping(ctx); err != nil {
+			return fmt.Errorf("failed to ping database: %w", err)
+		}
+		return nil
+	})
+}
+
+// MigrationChecker はDBのマイグレーションのバージョンがexpectedと一致し、dirtyでないかを確認するチェック
+func (c *Client) MigrationChecker(expected uint) health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		var version int64
+		var dirty bool
+		err := c.Pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("no migrations have been applied (expected version %d)", expected)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read migration version: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("migration version %d is dirty", version)
+		}
+		if uint(version) != expected {
+			return fmt.Errorf("database is at migration version %d, expected %d", version, expected)
+		}
+		return nil
+	})
+}
+
+// PoolChecker は使用中の接続数が最大接続数のthreshold（0〜1）以上に達していないかを確認するチェック
+func (c *Client) PoolChecker(threshold float64) health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		stats := c.Pool.Stat()
+		if stats.MaxConns() <= 0 {
+			return nil
+		}
+		saturation := float64(stats.AcquiredConns()) / float64(stats.MaxConns())
+		if saturation >= threshold {
+			return fmt.Errorf("connection pool is saturated: %d of %d connections in use, %d waits so far",
+				stats.AcquiredConns(), stats.MaxConns(), stats.EmptyAcquireCount())
+		}
+		return nil
+	})
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/logging"
+)
+
+// QueryHook はsqlcクエリの実行前に呼ばれ、戻り値の関数がクエリの完了後にエラー（成功時はnil）を受け取って呼ばれる
+// メトリクスやトレースの記録に使う。nameはクエリ名（"-- name: GetSystems :many" のGetSystems）
+type QueryHook func(ctx context.Context, name string) (context.Context, func(err error))
+
+// unknownQueryName はsqlcのクエリ名コメントがないSQLに付ける名前
+const unknownQueryName = "unknown"
+
+// hookedDBTX はDBTXの各呼び出しでQueryHookを実行するラッパー
+// Query・QueryRowの計測は行を読み終えるまで（RowsのClose、RowのScan）を含む
+type hookedDBTX struct {
+	inner DBTX
+	hooks []QueryHook
+}
+
+// WithQueryHooks はinnerの呼び出しごとにhooksを実行するDBTXを返す
+func WithQueryHooks(inner DBTX, hooks ...QueryHook) DBTX {
+	if len(hooks) == 0 {
+		return inner
+	}
+	return &hookedDBTX{inner: inner, hooks: hooks}
+}
+
+func (h *hookedDBTX) start(ctx context.Context, name string) (context.Context, func(err error)) {
+	finishers := make([]func(error), 0, len(h.hooks))
+	for _, hook := range h.hooks {
+		var finish func(error)
+		ctx, finish = hook(ctx, name)
+		finishers = append(finishers, finish)
+	}
+	return ctx, func(err error) {
+		// 開始と逆順に終了する（トレースのスパンを正しく入れ子にするため）
+		for i := len(finishers) - 1; i >= 0; i-- {
+			finishers[i](err)
+		}
+	}
+}
+
+func (h *hookedDBTX) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, finish := h.start(ctx, QueryName(query))
+	tag, err := h.inner.Exec(ctx, query, args...)
+	finish(err)
+	return tag, err
+}
+
+func (h *hookedDBTX) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	ctx, finish := h.start(ctx, QueryName(query))
+	rows, err := h.inner.Query(ctx, query, args...)
+	if err != nil {
+		finish(err)
+		return nil, err
+	}
+	return &hookedRows{Rows: rows, finish: finish}, nil
+}
+
+func (h *hookedDBTX) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	ctx, finish := h.start(ctx, QueryName(query))
+	return &hookedRow{row: h.inner.QueryRow(ctx, query, args...), finish: finish}
+}
+
+// CopyFrom はCOPYで書き込む。フックにはテーブル名を "CopyFrom:system" の形で渡す
+func (h *hookedDBTX) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	ctx, finish := h.start(ctx, "CopyFrom:"+tableName[len(tableName)-1])
+	n, err := h.inner.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	finish(err)
+	return n, err
+}
+
+// hookedRows はClose時にフックを終了するRows
+type hookedRows struct {
+	pgx.Rows
+	finish func(err error)
+	closed bool
+}
+
+func (r *hookedRows) Close() {
+	r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		r.finish(r.Rows.Err())
+	}
+}
+
+// hookedRow はScan時にフックを終了するRow（QueryRowのエラーはScanで返る）
+type hookedRow struct {
+	row    pgx.Row
+	finish func(err error)
+}
+
+func (r *hookedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	r.finish(err)
+	return err
+}
+
+// LogQueryHook はクエリの完了をdatabaseロガーに記録するQueryHook
+// 成功はDebug、失敗（pgx.ErrNoRowsを除く）はWarnで記録する。LOG_LEVELS=database=debug で全クエリを確認できる
+func LogQueryHook(ctx context.Context, name string) (context.Context, func(err error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		logger := logging.Named(logging.FromContext(ctx), logging.LoggerDatabase)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("Query failed", zap.String("query", name), zap.Duration("duration", time.Since(start)), zap.Error(err))
+			return
+		}
+		logger.Debug("Query executed", zap.String("query", name), zap.Duration("duration", time.Since(start)))
+	}
+}
+
+// QueryName はsqlcが生成したSQLの先頭の "-- name: X :kind" コメントからクエリ名を取り出す
+func QueryName(query string) string {
+	const prefix = "-- name: "
+	if !strings.HasPrefix(query, prefix) {
+		return unknownQueryName
+	}
+	rest := query[len(prefix):]
+	if i := strings.IndexAny(rest, " \n"); i >= 0 {
+		rest = rest[:i]
+	}
+	if rest == "" {
+		return unknownQueryName
+	}
+	return rest
+}
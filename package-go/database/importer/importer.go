@@ -0,0 +1,317 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"sample-micro-service-api/package-go/database/internal/db"
+)
+
+// Row はファイルから読み込んだ1行分のシステム情報
+type Row struct {
+	Line              int // ファイル上の行番号（ヘッダー行を1行目とする）
+	SystemName        string
+	SystemNameKana    string
+	LocalGovernmentId string
+	PrefectureName    string
+	CityName          string
+	MailAddress       string
+	Telephone         string
+	Remark            string
+}
+
+// Action は行ごとの取り込み結果の種別
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionError  Action = "error"
+)
+
+// FieldError は行内の項目ごとの検証エラー
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// RowResult は1行分の取り込み結果
+type RowResult struct {
+	Line              int
+	Action            Action
+	SystemName        string
+	SystemID          *uuid.UUID // 更新対象の既存ID、またはコミット後の新規ID
+	LocalGovernmentId *string    // 解決済みの自治体ID
+	Errors            []FieldError
+
+	params db.CreateSystemParams
+}
+
+// Report は取り込み全体の結果
+type Report struct {
+	DryRun    bool
+	Committed bool
+	Total     int
+	Creates   int
+	Updates   int
+	Errors    int
+	Rows      []RowResult
+}
+
+// Options は取り込み時のオプション
+type Options struct {
+	DryRun bool // trueの場合は検証結果のみを返し、DBへは反映しない
+}
+
+// TxRunner はfnを1つのトランザクションで実行する（database.Client）
+type TxRunner interface {
+	InTx(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context, q *db.Queries) error) error
+}
+
+// errRollback はDryRun・検証エラーの際に書き込まずにトランザクションを終えるための内部エラー
+var errRollback = errors.New("rollback import")
+
+// Importer はシステム一覧ファイルをDBへ取り込む
+type Importer struct {
+	runner TxRunner
+}
+
+// New はImporterの新しいインスタンスを作成
+func New(runner TxRunner) *Importer {
+	return &Importer{runner: runner}
+}
+
+// Import は全行を検証し、DryRunでなくエラーもなければ1トランザクションで反映
+// 既存システムとはシステム名（system_systemName_unique）で照合し、一致すれば更新、なければ作成する
+func (im *Importer) Import(ctx context.Context, rows []Row, opts Options) (*Report, error) {
+	var report *Report
+	err := im.runner.InTx(ctx, pgx.TxOptions{}, func(ctx context.Context, queries *db.Queries) error {
+		var err error
+		report, err = plan(ctx, queries, rows)
+		if err != nil {
+			return err
+		}
+		report.DryRun = opts.DryRun
+
+		if opts.DryRun || report.Errors > 0 {
+			return errRollback
+		}
+		return apply(ctx, queries, report)
+	})
+	if errors.Is(err, errRollback) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to import systems: %w", err)
+	}
+	report.Committed = true
+
+	return report, nil
+}
+
+// apply は計画どおりに書き込む。新規作成はCOPYでまとめて書き込み、採番されたIDをシステム名で引き直す
+func apply(ctx context.Context, queries *db.Queries, report *Report) error {
+	var creates []db.CopySystemsParams
+	var names []string
+	for i := range report.Rows {
+		result := &report.Rows[i]
+		switch result.Action {
+		case ActionCreate:
+			creates = append(creates, db.CopySystemsParams(result.params))
+			names = append(names, result.SystemName)
+		case ActionUpdate:
+			_, err := queries.UpdateSystem(ctx, db.UpdateSystemParams{
+				ID:                *result.SystemID,
+				SystemName:        result.params.SystemName,
+				LocalGovernmentId: result.params.LocalGovernmentId,
+				MailAddress:       result.params.MailAddress,
+				Telephone:         result.params.Telephone,
+				Remark:            result.params.Remark,
+				SystemNameKana:    result.params.SystemNameKana,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update system at line %d: %w", result.Line, err)
+			}
+		}
+	}
+	if len(creates) == 0 {
+		return nil
+	}
+
+	if _, err := queries.CopySystems(ctx, creates); err != nil {
+		return fmt.Errorf("failed to create systems: %w", err)
+	}
+	created, err := queries.GetSystemIDsByNames(ctx, names)
+	if err != nil {
+		return fmt.Errorf("failed to look up created systems: %w", err)
+	}
+	ids := make(map[string]uuid.UUID, len(created))
+	for _, row := range created {
+		ids[row.SystemName] = row.ID
+	}
+	for i := range report.Rows {
+		result := &report.Rows[i]
+		if id, ok := ids[result.SystemName]; ok && result.Action == ActionCreate {
+			result.SystemID = &id
+		}
+	}
+	return nil
+}
+
+// plan は各行を検証し、作成・更新・エラーのいずれになるかを判定
+func plan(ctx context.Context, queries *db.Queries, rows []Row) (*Report, error) {
+	report := &Report{Total: len(rows)}
+	seen := make(map[string]int)
+
+	for _, row := range rows {
+		result := RowResult{
+			Line:       row.Line,
+			SystemName: row.SystemName,
+		}
+		result.Errors = validateRow(row)
+
+		if row.SystemName != "" {
+			if line, ok := seen[row.SystemName]; ok {
+				result.Errors = append(result.Errors, FieldError{
+					Field:   "systemName",
+					Message: fmt.Sprintf("duplicate systemName (first seen at line %d)", line),
+				})
+			} else {
+				seen[row.SystemName] = row.Line
+			}
+		}
+
+		localGovernmentId, fieldErr, err := resolveLocalGovernment(ctx, queries, row)
+		if err != nil {
+			return nil, err
+		}
+		if fieldErr != nil {
+			result.Errors = append(result.Errors, *fieldErr)
+		}
+		result.LocalGovernmentId = localGovernmentId
+
+		if len(result.Errors) > 0 {
+			result.Action = ActionError
+			report.Errors++
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		result.params = db.CreateSystemParams{
+			SystemName:        row.SystemName,
+			LocalGovernmentId: localGovernmentId,
+			MailAddress:       row.MailAddress,
+			Telephone:         emptyToNil(row.Telephone),
+			Remark:            emptyToNil(row.Remark),
+			SystemNameKana:    emptyToNil(row.SystemNameKana),
+		}
+
+		existing, err := queries.GetSystemByName(ctx, row.SystemName)
+		switch {
+		case err == nil:
+			result.Action = ActionUpdate
+			result.SystemID = &existing.ID
+			report.Updates++
+		case errors.Is(err, pgx.ErrNoRows):
+			result.Action = ActionCreate
+			report.Creates++
+		default:
+			return nil, fmt.Errorf("failed to look up system at line %d: %w", row.Line, err)
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+// validateRow はDBの列定義に合わせて各項目を検証
+func validateRow(row Row) []FieldError {
+	var errs []FieldError
+
+	if row.SystemName == "" {
+		errs = append(errs, FieldError{Field: "systemName", Message: "systemName is required"})
+	} else if utf8.RuneCountInString(row.SystemName) > 255 {
+		errs = append(errs, FieldError{Field: "systemName", Message: "systemName must be at most 255 characters"})
+	}
+
+	if row.MailAddress == "" {
+		errs = append(errs, FieldError{Field: "mailAddress", Message: "mailAddress is required"})
+	} else if utf8.RuneCountInString(row.MailAddress) > 255 {
+		errs = append(errs, FieldError{Field: "mailAddress", Message: "mailAddress must be at most 255 characters"})
+	} else if addr, err := mail.ParseAddress(row.MailAddress); err != nil || addr.Address != row.MailAddress {
+		errs = append(errs, FieldError{Field: "mailAddress", Message: "mailAddress is not a valid email address"})
+	}
+
+	if utf8.RuneCountInString(row.SystemNameKana) > 255 {
+		errs = append(errs, FieldError{Field: "systemNameKana", Message: "systemNameKana must be at most 255 characters"})
+	}
+
+	if utf8.RuneCountInString(row.Telephone) > 255 {
+		errs = append(errs, FieldError{Field: "telephone", Message: "telephone must be at most 255 characters"})
+	}
+
+	if utf8.RuneCountInString(row.Remark) > 1000 {
+		errs = append(errs, FieldError{Field: "remark", Message: "remark must be at most 1000 characters"})
+	}
+
+	return errs
+}
+
+// resolveLocalGovernment は自治体コード、または都道府県名＋市区町村名から自治体IDを解決
+func resolveLocalGovernment(ctx context.Context, queries *db.Queries, row Row) (*string, *FieldError, error) {
+	if row.LocalGovernmentId != "" {
+		lg, err := queries.GetLocalGovernment(ctx, row.LocalGovernmentId)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &FieldError{
+				Field:   "localGovernmentId",
+				Message: fmt.Sprintf("local government %q does not exist", row.LocalGovernmentId),
+			}, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to look up local government at line %d: %w", row.Line, err)
+		}
+		return &lg.ID, nil, nil
+	}
+
+	if row.CityName == "" {
+		return nil, nil, nil
+	}
+
+	candidates, err := queries.FindLocalGovernmentsByName(ctx, db.FindLocalGovernmentsByNameParams{
+		CityName:       row.CityName,
+		PrefectureName: row.PrefectureName,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to look up local government at line %d: %w", row.Line, err)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, &FieldError{
+			Field:   "cityName",
+			Message: fmt.Sprintf("local government %q does not exist", row.PrefectureName+row.CityName),
+		}, nil
+	case 1:
+		return &candidates[0].ID, nil, nil
+	default:
+		return nil, &FieldError{
+			Field:   "cityName",
+			Message: fmt.Sprintf("local government %q is ambiguous; specify prefectureName or localGovernmentId", row.CityName),
+		}, nil
+	}
+}
+
+// emptyToNil は空文字をNULLとして扱う
+func emptyToNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
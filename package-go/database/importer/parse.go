@@ -0,0 +1,271 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInvalidFile はファイル形式・ヘッダー・文字コードが不正な場合のエラー
+var ErrInvalidFile = errors.New("invalid import file")
+
+// Format は取り込みファイルの形式
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// Encoding はCSVファイルの文字コード
+type Encoding string
+
+const (
+	EncodingAuto     Encoding = "auto"
+	EncodingUTF8     Encoding = "utf-8"
+	EncodingShiftJIS Encoding = "shift_jis"
+)
+
+// utf8BOM はExcelが出力するUTF-8 CSVの先頭に付与されるBOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// DetectFormat はファイル名の拡張子から取り込み形式を判定
+func DetectFormat(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported file extension %q (expected .csv or .xlsx)", ErrInvalidFile, filepath.Ext(filename))
+	}
+}
+
+// ParseEncoding は文字列から文字コードを判定（空文字はauto扱い）
+func ParseEncoding(s string) (Encoding, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto":
+		return EncodingAuto, nil
+	case "utf-8", "utf8":
+		return EncodingUTF8, nil
+	case "shift_jis", "shift-jis", "sjis", "cp932":
+		return EncodingShiftJIS, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported encoding %q", ErrInvalidFile, s)
+	}
+}
+
+// Parse はCSV/XLSXを読み込み、ヘッダー行に従って取り込み行へ変換
+func Parse(r io.Reader, format Format, encoding Encoding) ([]Row, error) {
+	var records [][]string
+	var lines []int
+	var err error
+
+	switch format {
+	case FormatCSV:
+		records, lines, err = readCSV(r, encoding)
+	case FormatXLSX:
+		records, lines, err = readXLSX(r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidFile, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapRecords(records, lines)
+}
+
+// readCSV はCSVを読み込む（BOM除去とShift_JISの自動判定を含む）
+// 各レコードの開始行も返す（引用符で囲んだ値に改行を含むレコードは複数行にわたるため）
+func readCSV(r io.Reader, encoding Encoding) ([][]string, []int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	if encoding == EncodingAuto {
+		if utf8.Valid(data) {
+			encoding = EncodingUTF8
+		} else {
+			encoding = EncodingShiftJIS
+		}
+	}
+
+	if encoding == EncodingShiftJIS {
+		data, err = japanese.ShiftJIS.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: failed to decode Shift_JIS: %v", ErrInvalidFile, err)
+		}
+	} else if !utf8.Valid(data) {
+		return nil, nil, fmt.Errorf("%w: file is not valid UTF-8", ErrInvalidFile)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	return records, lines, nil
+}
+
+// readXLSX はXLSXの先頭シートを読み込む（空の行も含むため、レコードの行番号はその位置）
+func readXLSX(r io.Reader) ([][]string, []int, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil, fmt.Errorf("%w: workbook has no sheets", ErrInvalidFile)
+	}
+
+	records, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	lines := make([]int, len(records))
+	for i := range lines {
+		lines[i] = i + 1
+	}
+	return records, lines, nil
+}
+
+// headerAliases は正規化済みヘッダー名と取り込み項目の対応表
+var headerAliases = map[string]string{
+	"systemname": "systemName",
+	"システム名":      "systemName",
+	"システム名称":     "systemName",
+
+	"systemnamekana": "systemNameKana",
+	"システム名カナ":        "systemNameKana",
+	"システム名かな":        "systemNameKana",
+	"フリガナ":           "systemNameKana",
+	"ふりがな":           "systemNameKana",
+
+	"localgovernmentid": "localGovernmentId",
+	"自治体id":             "localGovernmentId",
+	"自治体コード":            "localGovernmentId",
+	"団体コード":             "localGovernmentId",
+	"地方公共団体コード":         "localGovernmentId",
+
+	"prefecturename": "prefectureName",
+	"都道府県":           "prefectureName",
+	"都道府県名":          "prefectureName",
+
+	"cityname": "cityName",
+	"市区町村":     "cityName",
+	"市区町村名":    "cityName",
+	"自治体名":     "cityName",
+
+	"mailaddress": "mailAddress",
+	"email":       "mailAddress",
+	"メールアドレス":     "mailAddress",
+
+	"telephone": "telephone",
+	"tel":       "telephone",
+	"電話番号":      "telephone",
+
+	"remark": "remark",
+	"備考":     "remark",
+}
+
+// requiredColumns はヘッダー行に必須の列
+var requiredColumns = []string{"systemName", "mailAddress"}
+
+// normalizeHeader は全角・半角、大文字・小文字、空白の揺れを吸収
+func normalizeHeader(h string) string {
+	h = norm.NFKC.String(strings.TrimPrefix(h, "\ufeff"))
+	h = strings.ToLower(h)
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(h)
+}
+
+// mapRecords はヘッダー行を解釈し、データ行をRowに変換（linesは各レコードのファイル上の行番号）
+func mapRecords(records [][]string, lines []int) ([]Row, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidFile)
+	}
+
+	columns := make(map[string]int)
+	for i, h := range records[0] {
+		if field, ok := headerAliases[normalizeHeader(h)]; ok {
+			if _, dup := columns[field]; dup {
+				return nil, fmt.Errorf("%w: duplicate column for %s", ErrInvalidFile, field)
+			}
+			columns[field] = i
+		}
+	}
+
+	var missing []string
+	for _, field := range requiredColumns {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing required columns: %s", ErrInvalidFile, strings.Join(missing, ", "))
+	}
+
+	cell := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []Row
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+		rows = append(rows, Row{
+			Line:              lines[i+1],
+			SystemName:        cell(record, "systemName"),
+			SystemNameKana:    cell(record, "systemNameKana"),
+			LocalGovernmentId: cell(record, "localGovernmentId"),
+			PrefectureName:    cell(record, "prefectureName"),
+			CityName:          cell(record, "cityName"),
+			MailAddress:       cell(record, "mailAddress"),
+			Telephone:         cell(record, "telephone"),
+			Remark:            cell(record, "remark"),
+		})
+	}
+
+	return rows, nil
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: copyfrom.go
+
+package db
+
+import (
+	"context"
+)
+
+// iteratorForCopySystems implements pgx.CopyFromSource.
+type iteratorForCopySystems struct {
+	rows                 []CopySystemsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopySystems) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopySystems) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].SystemName,
+		r.rows[0].LocalGovernmentId,
+		r.rows[0].MailAddress,
+		r.rows[0].Telephone,
+		r.rows[0].Remark,
+		r.rows[0].SystemNameKana,
+	}, nil
+}
+
+func (r iteratorForCopySystems) Err() error {
+	return nil
+}
+
+// 一括取り込みの新規作成用（COPYで書き込む）
+func (q *Queries) CopySystems(ctx context.Context, arg []CopySystemsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"public", "system"}, []string{"systemName", "localGovernmentId", "mailAddress", "telephone", "remark", "systemNameKana"}, &iteratorForCopySystems{rows: arg})
+}
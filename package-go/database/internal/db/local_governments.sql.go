@@ -0,0 +1,74 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: local_governments.sql
+
+package db
+
+import (
+	"context"
+)
+
+const findLocalGovernmentsByName = `-- name: FindLocalGovernmentsByName :many
+SELECT id, "prefectureName", "cityName", "prefectureNameKana", "cityNameKana",
+       "createdAt", "updatedAt"
+FROM public."m_localGovernment"
+WHERE "cityName" = $1::text
+  AND (CASE WHEN $2::text != '' THEN "prefectureName" = $2::text ELSE TRUE END)
+ORDER BY id
+`
+
+type FindLocalGovernmentsByNameParams struct {
+	CityName       string `json:"city_name"`
+	PrefectureName string `json:"prefecture_name"`
+}
+
+func (q *Queries) FindLocalGovernmentsByName(ctx context.Context, arg FindLocalGovernmentsByNameParams) ([]MLocalGovernment, error) {
+	rows, err := q.db.Query(ctx, findLocalGovernmentsByName, arg.CityName, arg.PrefectureName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MLocalGovernment
+	for rows.Next() {
+		var i MLocalGovernment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PrefectureName,
+			&i.CityName,
+			&i.PrefectureNameKana,
+			&i.CityNameKana,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLocalGovernment = `-- name: GetLocalGovernment :one
+SELECT id, "prefectureName", "cityName", "prefectureNameKana", "cityNameKana",
+       "createdAt", "updatedAt"
+FROM public."m_localGovernment"
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetLocalGovernment(ctx context.Context, id string) (MLocalGovernment, error) {
+	row := q.db.QueryRow(ctx, getLocalGovernment, id)
+	var i MLocalGovernment
+	err := row.Scan(
+		&i.ID,
+		&i.PrefectureName,
+		&i.CityName,
+		&i.PrefectureNameKana,
+		&i.CityNameKana,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -5,7 +5,6 @@
 package db
 
 import (
-	"database/sql"
 	"encoding/json"
 	"time"
 
@@ -13,17 +12,17 @@ import (
 )
 
 type DrizzleDrizzleMigration struct {
-	ID        int32         `json:"id"`
-	Hash      string        `json:"hash"`
-	CreatedAt sql.NullInt64 `json:"created_at"`
+	ID        int32  `json:"id"`
+	Hash      string `json:"hash"`
+	CreatedAt *int64 `json:"created_at"`
 }
 
 type GcasGroup struct {
-	ID              uuid.UUID     `json:"id"`
-	GroupCategoryId sql.NullInt32 `json:"groupCategoryId"`
-	GroupName       string        `json:"groupName"`
-	CreatedAt       time.Time     `json:"createdAt"`
-	UpdatedAt       time.Time     `json:"updatedAt"`
+	ID              uuid.UUID `json:"id"`
+	GroupCategoryId *int32    `json:"groupCategoryId"`
+	GroupName       string    `json:"groupName"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
 }
 
 type GcasGroupSystemRelation struct {
@@ -42,14 +41,14 @@ type GcasGroupUserRelation struct {
 }
 
 type GcasUser struct {
-	ID                     uuid.UUID     `json:"id"`
-	FamilyName             string        `json:"familyName"`
-	GivenName              string        `json:"givenName"`
-	MailAddress            string        `json:"mailAddress"`
-	OrganizationCategoryId sql.NullInt32 `json:"organizationCategoryId"`
-	CreatedAt              time.Time     `json:"createdAt"`
-	UpdatedAt              time.Time     `json:"updatedAt"`
-	LastLoginAt            sql.NullTime  `json:"lastLoginAt"`
+	ID                     uuid.UUID  `json:"id"`
+	FamilyName             string     `json:"familyName"`
+	GivenName              string     `json:"givenName"`
+	MailAddress            string     `json:"mailAddress"`
+	OrganizationCategoryId *int32     `json:"organizationCategoryId"`
+	CreatedAt              time.Time  `json:"createdAt"`
+	UpdatedAt              time.Time  `json:"updatedAt"`
+	LastLoginAt            *time.Time `json:"lastLoginAt"`
 }
 
 type MLocalGovernment struct {
@@ -79,25 +78,25 @@ type MUserRole struct {
 }
 
 type Project struct {
-	ID                            uuid.UUID     `json:"id"`
-	ProjectName                   string        `json:"projectName"`
-	LocalGovernmentId             string        `json:"localGovernmentId"`
-	ProjectType                   string        `json:"projectType"`
-	GovernmentCloudConnectionType string        `json:"governmentCloudConnectionType"`
-	CreatedAt                     time.Time     `json:"createdAt"`
-	UpdatedAt                     time.Time     `json:"updatedAt"`
-	CorporateNumber               string        `json:"corporateNumber"`
-	VendorName                    string        `json:"vendorName"`
-	ServiceOutsourcingFee         sql.NullInt32 `json:"serviceOutsourcingFee"`
-	CloudUsageFee                 sql.NullInt32 `json:"cloudUsageFee"`
+	ID                            uuid.UUID `json:"id"`
+	ProjectName                   string    `json:"projectName"`
+	LocalGovernmentId             string    `json:"localGovernmentId"`
+	ProjectType                   string    `json:"projectType"`
+	GovernmentCloudConnectionType string    `json:"governmentCloudConnectionType"`
+	CreatedAt                     time.Time `json:"createdAt"`
+	UpdatedAt                     time.Time `json:"updatedAt"`
+	CorporateNumber               string    `json:"corporateNumber"`
+	VendorName                    string    `json:"vendorName"`
+	ServiceOutsourcingFee         *int32    `json:"serviceOutsourcingFee"`
+	CloudUsageFee                 *int32    `json:"cloudUsageFee"`
 }
 
 type ProjectCost struct {
-	ProjectId uuid.UUID     `json:"projectId"`
-	Year      int32         `json:"year"`
-	Cost      sql.NullInt32 `json:"cost"`
-	CreatedAt time.Time     `json:"createdAt"`
-	UpdatedAt time.Time     `json:"updatedAt"`
+	ProjectId uuid.UUID `json:"projectId"`
+	Year      int32     `json:"year"`
+	Cost      *int32    `json:"cost"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type ProjectSystemRelation struct {
@@ -108,14 +107,16 @@ type ProjectSystemRelation struct {
 }
 
 type System struct {
-	ID                uuid.UUID      `json:"id"`
-	SystemName        string         `json:"systemName"`
-	LocalGovernmentId sql.NullString `json:"localGovernmentId"`
-	CreatedAt         time.Time      `json:"createdAt"`
-	UpdatedAt         time.Time      `json:"updatedAt"`
-	MailAddress       string         `json:"mailAddress"`
-	Telephone         sql.NullString `json:"telephone"`
-	Remark            sql.NullString `json:"remark"`
+	ID                uuid.UUID `json:"id"`
+	SystemName        string    `json:"systemName"`
+	LocalGovernmentId *string   `json:"localGovernmentId"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+	MailAddress       string    `json:"mailAddress"`
+	Telephone         *string   `json:"telephone"`
+	Remark            *string   `json:"remark"`
+	SystemNameKana    *string   `json:"systemNameKana"`
+	SearchText        string    `json:"searchText"`
 }
 
 type SystemBasicInformation struct {
@@ -128,3 +129,34 @@ type SystemBasicInformation struct {
 	CreatedAt            time.Time       `json:"createdAt"`
 	UpdatedAt            time.Time       `json:"updatedAt"`
 }
+
+type Vendor struct {
+	CorporateNumber string     `json:"corporateNumber"`
+	VendorName      string     `json:"vendorName"`
+	VendorNameKana  *string    `json:"vendorNameKana"`
+	PrefectureName  *string    `json:"prefectureName"`
+	CityName        *string    `json:"cityName"`
+	StreetNumber    *string    `json:"streetNumber"`
+	PostCode        *string    `json:"postCode"`
+	CloseDate       *time.Time `json:"closeDate"`
+	NtaUpdatedAt    time.Time  `json:"ntaUpdatedAt"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+type VendorDirectory struct {
+	CorporateNumber string     `json:"corporateNumber"`
+	VendorName      string     `json:"vendorName"`
+	VendorNameKana  *string    `json:"vendorNameKana"`
+	PrefectureName  *string    `json:"prefectureName"`
+	CityName        *string    `json:"cityName"`
+	StreetNumber    *string    `json:"streetNumber"`
+	PostCode        *string    `json:"postCode"`
+	CloseDate       *time.Time `json:"closeDate"`
+	NtaUpdatedAt    *time.Time `json:"ntaUpdatedAt"`
+}
+
+type VendorProject struct {
+	CorporateNumber string    `json:"corporateNumber"`
+	ProjectId       uuid.UUID `json:"projectId"`
+}
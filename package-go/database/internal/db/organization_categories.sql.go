@@ -0,0 +1,61 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: organization_categories.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getOrganizationCategory = `-- name: GetOrganizationCategory :one
+SELECT id, "organizationCategoryNameJa", "organizationCategoryNameEn", "createdAt", "updatedAt"
+FROM public."m_organizationCategory"
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetOrganizationCategory(ctx context.Context, id int32) (MOrganizationCategory, error) {
+	row := q.db.QueryRow(ctx, getOrganizationCategory, id)
+	var i MOrganizationCategory
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationCategoryNameJa,
+		&i.OrganizationCategoryNameEn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listOrganizationCategories = `-- name: ListOrganizationCategories :many
+SELECT id, "organizationCategoryNameJa", "organizationCategoryNameEn", "createdAt", "updatedAt"
+FROM public."m_organizationCategory"
+ORDER BY id
+`
+
+func (q *Queries) ListOrganizationCategories(ctx context.Context) ([]MOrganizationCategory, error) {
+	rows, err := q.db.Query(ctx, listOrganizationCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MOrganizationCategory
+	for rows.Next() {
+		var i MOrganizationCategory
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationCategoryNameJa,
+			&i.OrganizationCategoryNameEn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
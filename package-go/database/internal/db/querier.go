@@ -6,22 +6,48 @@ package db
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	// 一括取り込みの新規作成用（COPYで書き込む）
+	CopySystems(ctx context.Context, arg []CopySystemsParams) (int64, error)
 	CreateSystem(ctx context.Context, arg CreateSystemParams) (System, error)
 	DeleteSystem(ctx context.Context, id uuid.UUID) error
+	FindLocalGovernmentsByName(ctx context.Context, arg FindLocalGovernmentsByNameParams) ([]MLocalGovernment, error)
+	// 正規化した検索語で部分一致（LIKE）または語類似度（<%）で絞り込み、類似度順に返す
+	FuzzySearchSystems(ctx context.Context, arg FuzzySearchSystemsParams) ([]FuzzySearchSystemsRow, error)
+	GetLocalGovernment(ctx context.Context, id string) (MLocalGovernment, error)
+	GetOrganizationCategory(ctx context.Context, id int32) (MOrganizationCategory, error)
 	GetSystem(ctx context.Context, id uuid.UUID) (System, error)
 	GetSystemByName(ctx context.Context, systemname string) (System, error)
+	GetSystemIDsByNames(ctx context.Context, systemNames []string) ([]GetSystemIDsByNamesRow, error)
 	GetSystems(ctx context.Context) ([]System, error)
 	GetSystemsByEmail(ctx context.Context, mailaddress string) ([]System, error)
-	GetSystemsByLocalGovernment(ctx context.Context, localgovernmentid sql.NullString) ([]System, error)
+	GetSystemsByLocalGovernment(ctx context.Context, localgovernmentid *string) ([]System, error)
+	GetUserRole(ctx context.Context, id int32) (MUserRole, error)
+	GlobalSearchGroups(ctx context.Context, arg GlobalSearchGroupsParams) ([]GlobalSearchGroupsRow, error)
+	GlobalSearchLocalGovernments(ctx context.Context, arg GlobalSearchLocalGovernmentsParams) ([]GlobalSearchLocalGovernmentsRow, error)
+	// 法人番号は数字のみのため前方一致で照合する
+	GlobalSearchProjects(ctx context.Context, arg GlobalSearchProjectsParams) ([]GlobalSearchProjectsRow, error)
+	// 横断検索（GET /api/v1/search）用。以下のGlobalSearch*も同じく、正規化した検索語で
+	// 部分一致（LIKE）または語類似度（<%）で絞り込み、スコア順に返す。
+	// 部分一致した行は類似度に関わらず上位になるようスコアを 1.0（備考のみの一致は 0.9）とする。
+	GlobalSearchSystems(ctx context.Context, arg GlobalSearchSystemsParams) ([]GlobalSearchSystemsRow, error)
+	ListOrganizationCategories(ctx context.Context) ([]MOrganizationCategory, error)
+	// プロジェクトまたはシステム基本情報に記載されている法人番号
+	ListReferencedCorporateNumbers(ctx context.Context) ([]string, error)
+	ListUserRoles(ctx context.Context) ([]MUserRole, error)
+	ListVendorProjects(ctx context.Context, corporateNumber string) ([]ListVendorProjectsRow, error)
+	// ベンダーのプロジェクトに紐づくシステム（複数プロジェクトで重複するものは1件にまとめる）
+	ListVendorSystems(ctx context.Context, corporateNumber string) ([]ListVendorSystemsRow, error)
+	ListVendors(ctx context.Context, corporateNumber string) ([]VendorDirectory, error)
 	SearchSystems(ctx context.Context, arg SearchSystemsParams) ([]System, error)
 	UpdateSystem(ctx context.Context, arg UpdateSystemParams) (System, error)
 	UpdateSystemContact(ctx context.Context, arg UpdateSystemContactParams) (System, error)
+	// 法人番号公表サイトの更新日が新しい場合のみ上書きする
+	UpsertVendor(ctx context.Context, arg UpsertVendorParams) error
 }
 
 var _ Querier = (*Queries)(nil)
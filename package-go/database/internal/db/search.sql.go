@@ -0,0 +1,238 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: search.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const globalSearchGroups = `-- name: GlobalSearchGroups :many
+WITH q AS (
+  SELECT public.normalize_search_text($2::text) AS text,
+         '%' || replace(replace(replace(public.normalize_search_text($2::text), '\', '\\'), '%', '\%'), '_', '\_') || '%' AS pattern
+)
+SELECT g.id, g."groupName",
+       (CASE
+         WHEN public.normalize_search_text(g."groupName") LIKE q.pattern THEN 1.0
+         ELSE word_similarity(q.text, public.normalize_search_text(g."groupName"))
+       END)::real AS score
+FROM public."gcasGroup" g, q
+WHERE public.normalize_search_text(g."groupName") LIKE q.pattern
+   OR q.text <% public.normalize_search_text(g."groupName")
+ORDER BY score DESC, g."groupName"
+LIMIT $1::int
+`
+
+type GlobalSearchGroupsParams struct {
+	MaxResults int32  `json:"max_results"`
+	Query      string `json:"query"`
+}
+
+type GlobalSearchGroupsRow struct {
+	ID        uuid.UUID `json:"id"`
+	GroupName string    `json:"groupName"`
+	Score     float32   `json:"score"`
+}
+
+func (q *Queries) GlobalSearchGroups(ctx context.Context, arg GlobalSearchGroupsParams) ([]GlobalSearchGroupsRow, error) {
+	rows, err := q.db.Query(ctx, globalSearchGroups, arg.MaxResults, arg.Query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GlobalSearchGroupsRow
+	for rows.Next() {
+		var i GlobalSearchGroupsRow
+		if err := rows.Scan(&i.ID, &i.GroupName, &i.Score); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const globalSearchLocalGovernments = `-- name: GlobalSearchLocalGovernments :many
+WITH q AS (
+  SELECT public.normalize_search_text($2::text) AS text,
+         '%' || replace(replace(replace(public.normalize_search_text($2::text), '\', '\\'), '%', '\%'), '_', '\_') || '%' AS pattern
+)
+SELECT lg.id, lg."prefectureName", lg."cityName", lg."cityNameKana",
+       (CASE
+         WHEN public.normalize_search_text(lg."cityName" || ' ' || lg."cityNameKana") LIKE q.pattern THEN 1.0
+         ELSE word_similarity(q.text, public.normalize_search_text(lg."cityName" || ' ' || lg."cityNameKana"))
+       END)::real AS score
+FROM public."m_localGovernment" lg, q
+WHERE public.normalize_search_text(lg."cityName" || ' ' || lg."cityNameKana") LIKE q.pattern
+   OR q.text <% public.normalize_search_text(lg."cityName" || ' ' || lg."cityNameKana")
+ORDER BY score DESC, lg.id
+LIMIT $1::int
+`
+
+type GlobalSearchLocalGovernmentsParams struct {
+	MaxResults int32  `json:"max_results"`
+	Query      string `json:"query"`
+}
+
+type GlobalSearchLocalGovernmentsRow struct {
+	ID             string  `json:"id"`
+	PrefectureName string  `json:"prefectureName"`
+	CityName       string  `json:"cityName"`
+	CityNameKana   string  `json:"cityNameKana"`
+	Score          float32 `json:"score"`
+}
+
+func (q *Queries) GlobalSearchLocalGovernments(ctx context.Context, arg GlobalSearchLocalGovernmentsParams) ([]GlobalSearchLocalGovernmentsRow, error) {
+	rows, err := q.db.Query(ctx, globalSearchLocalGovernments, arg.MaxResults, arg.Query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GlobalSearchLocalGovernmentsRow
+	for rows.Next() {
+		var i GlobalSearchLocalGovernmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PrefectureName,
+			&i.CityName,
+			&i.CityNameKana,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const globalSearchProjects = `-- name: GlobalSearchProjects :many
+WITH q AS (
+  SELECT public.normalize_search_text($2::text) AS text,
+         '%' || replace(replace(replace(public.normalize_search_text($2::text), '\', '\\'), '%', '\%'), '_', '\_') || '%' AS pattern
+)
+SELECT p.id, p."projectName", p."vendorName", p."corporateNumber", p."localGovernmentId",
+       (CASE
+         WHEN p."corporateNumber" LIKE ltrim(q.pattern, '%') THEN 1.0
+         WHEN public.normalize_search_text(p."projectName" || ' ' || p."vendorName") LIKE q.pattern THEN 1.0
+         ELSE word_similarity(q.text, public.normalize_search_text(p."projectName" || ' ' || p."vendorName"))
+       END)::real AS score
+FROM public.project p, q
+WHERE p."corporateNumber" LIKE ltrim(q.pattern, '%')
+   OR public.normalize_search_text(p."projectName" || ' ' || p."vendorName") LIKE q.pattern
+   OR q.text <% public.normalize_search_text(p."projectName" || ' ' || p."vendorName")
+ORDER BY score DESC, p."projectName"
+LIMIT $1::int
+`
+
+type GlobalSearchProjectsParams struct {
+	MaxResults int32  `json:"max_results"`
+	Query      string `json:"query"`
+}
+
+type GlobalSearchProjectsRow struct {
+	ID                uuid.UUID `json:"id"`
+	ProjectName       string    `json:"projectName"`
+	VendorName        string    `json:"vendorName"`
+	CorporateNumber   string    `json:"corporateNumber"`
+	LocalGovernmentId string    `json:"localGovernmentId"`
+	Score             float32   `json:"score"`
+}
+
+// 法人番号は数字のみのため前方一致で照合する
+func (q *Queries) GlobalSearchProjects(ctx context.Context, arg GlobalSearchProjectsParams) ([]GlobalSearchProjectsRow, error) {
+	rows, err := q.db.Query(ctx, globalSearchProjects, arg.MaxResults, arg.Query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GlobalSearchProjectsRow
+	for rows.Next() {
+		var i GlobalSearchProjectsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectName,
+			&i.VendorName,
+			&i.CorporateNumber,
+			&i.LocalGovernmentId,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const globalSearchSystems = `-- name: GlobalSearchSystems :many
+WITH q AS (
+  SELECT public.normalize_search_text($2::text) AS text,
+         '%' || replace(replace(replace(public.normalize_search_text($2::text), '\', '\\'), '%', '\%'), '_', '\_') || '%' AS pattern
+)
+SELECT s.id, s."systemName", s."systemNameKana", s.remark,
+       GREATEST(
+         CASE WHEN s."searchText" LIKE q.pattern THEN 1.0 ELSE word_similarity(q.text, s."searchText") END,
+         CASE WHEN public.normalize_search_text(s.remark) LIKE q.pattern THEN 0.9 ELSE COALESCE(word_similarity(q.text, public.normalize_search_text(s.remark)), 0) END
+       )::real AS score
+FROM public.system s, q
+WHERE s."searchText" LIKE q.pattern
+   OR q.text <% s."searchText"
+   OR public.normalize_search_text(s.remark) LIKE q.pattern
+   OR q.text <% public.normalize_search_text(s.remark)
+ORDER BY score DESC, s."systemName"
+LIMIT $1::int
+`
+
+type GlobalSearchSystemsParams struct {
+	MaxResults int32  `json:"max_results"`
+	Query      string `json:"query"`
+}
+
+type GlobalSearchSystemsRow struct {
+	ID             uuid.UUID `json:"id"`
+	SystemName     string    `json:"systemName"`
+	SystemNameKana *string   `json:"systemNameKana"`
+	Remark         *string   `json:"remark"`
+	Score          float32   `json:"score"`
+}
+
+// 横断検索（GET /api/v1/search）用。以下のGlobalSearch*も同じく、正規化した検索語で
+// 部分一致（LIKE）または語類似度（<%）で絞り込み、スコア順に返す。
+// 部分一致した行は類似度に関わらず上位になるようスコアを 1.0（備考のみの一致は 0.9）とする。
+func (q *Queries) GlobalSearchSystems(ctx context.Context, arg GlobalSearchSystemsParams) ([]GlobalSearchSystemsRow, error) {
+	rows, err := q.db.Query(ctx, globalSearchSystems, arg.MaxResults, arg.Query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GlobalSearchSystemsRow
+	for rows.Next() {
+		var i GlobalSearchSystemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SystemName,
+			&i.SystemNameKana,
+			&i.Remark,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
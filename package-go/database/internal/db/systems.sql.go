@@ -7,33 +7,44 @@ package db
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+type CopySystemsParams struct {
+	SystemName        string  `json:"systemName"`
+	LocalGovernmentId *string `json:"localGovernmentId"`
+	MailAddress       string  `json:"mailAddress"`
+	Telephone         *string `json:"telephone"`
+	Remark            *string `json:"remark"`
+	SystemNameKana    *string `json:"systemNameKana"`
+}
+
 const createSystem = `-- name: CreateSystem :one
-INSERT INTO public.system ("systemName", "localGovernmentId", "mailAddress", telephone, remark)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO public.system ("systemName", "localGovernmentId", "mailAddress", telephone, remark, "systemNameKana")
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-          "mailAddress", telephone, remark
+          "mailAddress", telephone, remark, "systemNameKana", "searchText"
 `
 
 type CreateSystemParams struct {
-	SystemName        string         `json:"systemName"`
-	LocalGovernmentId sql.NullString `json:"localGovernmentId"`
-	MailAddress       string         `json:"mailAddress"`
-	Telephone         sql.NullString `json:"telephone"`
-	Remark            sql.NullString `json:"remark"`
+	SystemName        string  `json:"systemName"`
+	LocalGovernmentId *string `json:"localGovernmentId"`
+	MailAddress       string  `json:"mailAddress"`
+	Telephone         *string `json:"telephone"`
+	Remark            *string `json:"remark"`
+	SystemNameKana    *string `json:"systemNameKana"`
 }
 
 func (q *Queries) CreateSystem(ctx context.Context, arg CreateSystemParams) (System, error) {
-	row := q.db.QueryRowContext(ctx, createSystem,
+	row := q.db.QueryRow(ctx, createSystem,
 		arg.SystemName,
 		arg.LocalGovernmentId,
 		arg.MailAddress,
 		arg.Telephone,
 		arg.Remark,
+		arg.SystemNameKana,
 	)
 	var i System
 	err := row.Scan(
@@ -45,6 +56,8 @@ func (q *Queries) CreateSystem(ctx context.Context, arg CreateSystemParams) (Sys
 		&i.MailAddress,
 		&i.Telephone,
 		&i.Remark,
+		&i.SystemNameKana,
+		&i.SearchText,
 	)
 	return i, err
 }
@@ -55,19 +68,95 @@ WHERE id = $1
 `
 
 func (q *Queries) DeleteSystem(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteSystem, id)
+	_, err := q.db.Exec(ctx, deleteSystem, id)
 	return err
 }
 
+const fuzzySearchSystems = `-- name: FuzzySearchSystems :many
+WITH q AS (
+  SELECT public.normalize_search_text($4::text) AS text
+)
+SELECT s.id, s."systemName", s."localGovernmentId", s."createdAt", s."updatedAt", 
+       s."mailAddress", s.telephone, s.remark, s."systemNameKana", s."searchText",
+       word_similarity(q.text, s."searchText")::real AS score
+FROM public.system s, q
+WHERE 
+  (s."searchText" LIKE '%' || replace(replace(replace(q.text, '\', '\\'), '%', '\%'), '_', '\_') || '%'
+   OR q.text <% s."searchText")
+  AND (CASE WHEN $1::text != '' THEN s."mailAddress" = $1::text ELSE TRUE END)
+  AND (CASE WHEN $2::text != '' THEN s."localGovernmentId" = $2::text ELSE TRUE END)
+ORDER BY score DESC, s."createdAt" DESC
+LIMIT $3::int
+`
+
+type FuzzySearchSystemsParams struct {
+	MailAddress       string `json:"mail_address"`
+	LocalGovernmentID string `json:"local_government_id"`
+	MaxResults        int32  `json:"max_results"`
+	Query             string `json:"query"`
+}
+
+type FuzzySearchSystemsRow struct {
+	ID                uuid.UUID `json:"id"`
+	SystemName        string    `json:"systemName"`
+	LocalGovernmentId *string   `json:"localGovernmentId"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+	MailAddress       string    `json:"mailAddress"`
+	Telephone         *string   `json:"telephone"`
+	Remark            *string   `json:"remark"`
+	SystemNameKana    *string   `json:"systemNameKana"`
+	SearchText        string    `json:"searchText"`
+	Score             float32   `json:"score"`
+}
+
+// 正規化した検索語で部分一致（LIKE）または語類似度（<%）で絞り込み、類似度順に返す
+func (q *Queries) FuzzySearchSystems(ctx context.Context, arg FuzzySearchSystemsParams) ([]FuzzySearchSystemsRow, error) {
+	rows, err := q.db.Query(ctx, fuzzySearchSystems,
+		arg.MailAddress,
+		arg.LocalGovernmentID,
+		arg.MaxResults,
+		arg.Query,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuzzySearchSystemsRow
+	for rows.Next() {
+		var i FuzzySearchSystemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SystemName,
+			&i.LocalGovernmentId,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MailAddress,
+			&i.Telephone,
+			&i.Remark,
+			&i.SystemNameKana,
+			&i.SearchText,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSystem = `-- name: GetSystem :one
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetSystem(ctx context.Context, id uuid.UUID) (System, error) {
-	row := q.db.QueryRowContext(ctx, getSystem, id)
+	row := q.db.QueryRow(ctx, getSystem, id)
 	var i System
 	err := row.Scan(
 		&i.ID,
@@ -78,19 +167,21 @@ func (q *Queries) GetSystem(ctx context.Context, id uuid.UUID) (System, error) {
 		&i.MailAddress,
 		&i.Telephone,
 		&i.Remark,
+		&i.SystemNameKana,
+		&i.SearchText,
 	)
 	return i, err
 }
 
 const getSystemByName = `-- name: GetSystemByName :one
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE "systemName" = $1 LIMIT 1
 `
 
 func (q *Queries) GetSystemByName(ctx context.Context, systemname string) (System, error) {
-	row := q.db.QueryRowContext(ctx, getSystemByName, systemname)
+	row := q.db.QueryRow(ctx, getSystemByName, systemname)
 	var i System
 	err := row.Scan(
 		&i.ID,
@@ -101,19 +192,52 @@ func (q *Queries) GetSystemByName(ctx context.Context, systemname string) (Syste
 		&i.MailAddress,
 		&i.Telephone,
 		&i.Remark,
+		&i.SystemNameKana,
+		&i.SearchText,
 	)
 	return i, err
 }
 
+const getSystemIDsByNames = `-- name: GetSystemIDsByNames :many
+SELECT id, "systemName"
+FROM public.system
+WHERE "systemName" = ANY($1::text[])
+`
+
+type GetSystemIDsByNamesRow struct {
+	ID         uuid.UUID `json:"id"`
+	SystemName string    `json:"systemName"`
+}
+
+func (q *Queries) GetSystemIDsByNames(ctx context.Context, systemNames []string) ([]GetSystemIDsByNamesRow, error) {
+	rows, err := q.db.Query(ctx, getSystemIDsByNames, systemNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSystemIDsByNamesRow
+	for rows.Next() {
+		var i GetSystemIDsByNamesRow
+		if err := rows.Scan(&i.ID, &i.SystemName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSystems = `-- name: GetSystems :many
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 ORDER BY "createdAt" DESC
 `
 
 func (q *Queries) GetSystems(ctx context.Context) ([]System, error) {
-	rows, err := q.db.QueryContext(ctx, getSystems)
+	rows, err := q.db.Query(ctx, getSystems)
 	if err != nil {
 		return nil, err
 	}
@@ -130,14 +254,13 @@ func (q *Queries) GetSystems(ctx context.Context) ([]System, error) {
 			&i.MailAddress,
 			&i.Telephone,
 			&i.Remark,
+			&i.SystemNameKana,
+			&i.SearchText,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -146,14 +269,14 @@ func (q *Queries) GetSystems(ctx context.Context) ([]System, error) {
 
 const getSystemsByEmail = `-- name: GetSystemsByEmail :many
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE "mailAddress" = $1
 ORDER BY "createdAt" DESC
 `
 
 func (q *Queries) GetSystemsByEmail(ctx context.Context, mailaddress string) ([]System, error) {
-	rows, err := q.db.QueryContext(ctx, getSystemsByEmail, mailaddress)
+	rows, err := q.db.Query(ctx, getSystemsByEmail, mailaddress)
 	if err != nil {
 		return nil, err
 	}
@@ -170,14 +293,13 @@ func (q *Queries) GetSystemsByEmail(ctx context.Context, mailaddress string) ([]
 			&i.MailAddress,
 			&i.Telephone,
 			&i.Remark,
+			&i.SystemNameKana,
+			&i.SearchText,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -186,14 +308,14 @@ func (q *Queries) GetSystemsByEmail(ctx context.Context, mailaddress string) ([]
 
 const getSystemsByLocalGovernment = `-- name: GetSystemsByLocalGovernment :many
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE "localGovernmentId" = $1
 ORDER BY "createdAt" DESC
 `
 
-func (q *Queries) GetSystemsByLocalGovernment(ctx context.Context, localgovernmentid sql.NullString) ([]System, error) {
-	rows, err := q.db.QueryContext(ctx, getSystemsByLocalGovernment, localgovernmentid)
+func (q *Queries) GetSystemsByLocalGovernment(ctx context.Context, localgovernmentid *string) ([]System, error) {
+	rows, err := q.db.Query(ctx, getSystemsByLocalGovernment, localgovernmentid)
 	if err != nil {
 		return nil, err
 	}
@@ -210,14 +332,13 @@ func (q *Queries) GetSystemsByLocalGovernment(ctx context.Context, localgovernme
 			&i.MailAddress,
 			&i.Telephone,
 			&i.Remark,
+			&i.SystemNameKana,
+			&i.SearchText,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -226,7 +347,7 @@ func (q *Queries) GetSystemsByLocalGovernment(ctx context.Context, localgovernme
 
 const searchSystems = `-- name: SearchSystems :many
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE 
   (CASE WHEN $1::text != '' THEN "systemName" ILIKE '%' || $1 || '%' ELSE TRUE END)
@@ -242,7 +363,7 @@ type SearchSystemsParams struct {
 }
 
 func (q *Queries) SearchSystems(ctx context.Context, arg SearchSystemsParams) ([]System, error) {
-	rows, err := q.db.QueryContext(ctx, searchSystems, arg.Column1, arg.Column2, arg.Column3)
+	rows, err := q.db.Query(ctx, searchSystems, arg.Column1, arg.Column2, arg.Column3)
 	if err != nil {
 		return nil, err
 	}
@@ -259,14 +380,13 @@ func (q *Queries) SearchSystems(ctx context.Context, arg SearchSystemsParams) ([
 			&i.MailAddress,
 			&i.Telephone,
 			&i.Remark,
+			&i.SystemNameKana,
+			&i.SearchText,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -276,29 +396,31 @@ func (q *Queries) SearchSystems(ctx context.Context, arg SearchSystemsParams) ([
 const updateSystem = `-- name: UpdateSystem :one
 UPDATE public.system
 SET "systemName" = $2, "localGovernmentId" = $3, "mailAddress" = $4, 
-    telephone = $5, remark = $6, "updatedAt" = now()
+    telephone = $5, remark = $6, "systemNameKana" = $7, "updatedAt" = now()
 WHERE id = $1
 RETURNING id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-          "mailAddress", telephone, remark
+          "mailAddress", telephone, remark, "systemNameKana", "searchText"
 `
 
 type UpdateSystemParams struct {
-	ID                uuid.UUID      `json:"id"`
-	SystemName        string         `json:"systemName"`
-	LocalGovernmentId sql.NullString `json:"localGovernmentId"`
-	MailAddress       string         `json:"mailAddress"`
-	Telephone         sql.NullString `json:"telephone"`
-	Remark            sql.NullString `json:"remark"`
+	ID                uuid.UUID `json:"id"`
+	SystemName        string    `json:"systemName"`
+	LocalGovernmentId *string   `json:"localGovernmentId"`
+	MailAddress       string    `json:"mailAddress"`
+	Telephone         *string   `json:"telephone"`
+	Remark            *string   `json:"remark"`
+	SystemNameKana    *string   `json:"systemNameKana"`
 }
 
 func (q *Queries) UpdateSystem(ctx context.Context, arg UpdateSystemParams) (System, error) {
-	row := q.db.QueryRowContext(ctx, updateSystem,
+	row := q.db.QueryRow(ctx, updateSystem,
 		arg.ID,
 		arg.SystemName,
 		arg.LocalGovernmentId,
 		arg.MailAddress,
 		arg.Telephone,
 		arg.Remark,
+		arg.SystemNameKana,
 	)
 	var i System
 	err := row.Scan(
@@ -310,6 +432,8 @@ func (q *Queries) UpdateSystem(ctx context.Context, arg UpdateSystemParams) (Sys
 		&i.MailAddress,
 		&i.Telephone,
 		&i.Remark,
+		&i.SystemNameKana,
+		&i.SearchText,
 	)
 	return i, err
 }
@@ -319,17 +443,17 @@ UPDATE public.system
 SET "mailAddress" = $2, telephone = $3, "updatedAt" = now()
 WHERE id = $1
 RETURNING id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-          "mailAddress", telephone, remark
+          "mailAddress", telephone, remark, "systemNameKana", "searchText"
 `
 
 type UpdateSystemContactParams struct {
-	ID          uuid.UUID      `json:"id"`
-	MailAddress string         `json:"mailAddress"`
-	Telephone   sql.NullString `json:"telephone"`
+	ID          uuid.UUID `json:"id"`
+	MailAddress string    `json:"mailAddress"`
+	Telephone   *string   `json:"telephone"`
 }
 
 func (q *Queries) UpdateSystemContact(ctx context.Context, arg UpdateSystemContactParams) (System, error) {
-	row := q.db.QueryRowContext(ctx, updateSystemContact, arg.ID, arg.MailAddress, arg.Telephone)
+	row := q.db.QueryRow(ctx, updateSystemContact, arg.ID, arg.MailAddress, arg.Telephone)
 	var i System
 	err := row.Scan(
 		&i.ID,
@@ -340,6 +464,8 @@ func (q *Queries) UpdateSystemContact(ctx context.Context, arg UpdateSystemConta
 		&i.MailAddress,
 		&i.Telephone,
 		&i.Remark,
+		&i.SystemNameKana,
+		&i.SearchText,
 	)
 	return i, err
 }
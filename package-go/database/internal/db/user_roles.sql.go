@@ -0,0 +1,61 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: user_roles.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getUserRole = `-- name: GetUserRole :one
+SELECT id, "roleNameJa", "roleNameEn", "createdAt", "updatedAt"
+FROM public."m_userRole"
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserRole(ctx context.Context, id int32) (MUserRole, error) {
+	row := q.db.QueryRow(ctx, getUserRole, id)
+	var i MUserRole
+	err := row.Scan(
+		&i.ID,
+		&i.RoleNameJa,
+		&i.RoleNameEn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listUserRoles = `-- name: ListUserRoles :many
+SELECT id, "roleNameJa", "roleNameEn", "createdAt", "updatedAt"
+FROM public."m_userRole"
+ORDER BY id
+`
+
+func (q *Queries) ListUserRoles(ctx context.Context) ([]MUserRole, error) {
+	rows, err := q.db.Query(ctx, listUserRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MUserRole
+	for rows.Next() {
+		var i MUserRole
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoleNameJa,
+			&i.RoleNameEn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
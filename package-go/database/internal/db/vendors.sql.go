@@ -0,0 +1,202 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: vendors.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const listReferencedCorporateNumbers = `-- name: ListReferencedCorporateNumbers :many
+SELECT DISTINCT "corporateNumber"::text AS "corporateNumber"
+FROM public."vendorProject"
+ORDER BY "corporateNumber"
+`
+
+// プロジェクトまたはシステム基本情報に記載されている法人番号
+func (q *Queries) ListReferencedCorporateNumbers(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listReferencedCorporateNumbers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var corporateNumber string
+		if err := rows.Scan(&corporateNumber); err != nil {
+			return nil, err
+		}
+		items = append(items, corporateNumber)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVendorProjects = `-- name: ListVendorProjects :many
+SELECT vp."corporateNumber", p.id, p."projectName", p."localGovernmentId"
+FROM public."vendorProject" vp
+JOIN public.project p ON p.id = vp."projectId"
+WHERE (CASE WHEN $1::text != '' THEN vp."corporateNumber" = $1::text ELSE TRUE END)
+ORDER BY vp."corporateNumber", p."projectName"
+`
+
+type ListVendorProjectsRow struct {
+	CorporateNumber   string    `json:"corporateNumber"`
+	ID                uuid.UUID `json:"id"`
+	ProjectName       string    `json:"projectName"`
+	LocalGovernmentId string    `json:"localGovernmentId"`
+}
+
+func (q *Queries) ListVendorProjects(ctx context.Context, corporateNumber string) ([]ListVendorProjectsRow, error) {
+	rows, err := q.db.Query(ctx, listVendorProjects, corporateNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVendorProjectsRow
+	for rows.Next() {
+		var i ListVendorProjectsRow
+		if err := rows.Scan(
+			&i.CorporateNumber,
+			&i.ID,
+			&i.ProjectName,
+			&i.LocalGovernmentId,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVendorSystems = `-- name: ListVendorSystems :many
+SELECT DISTINCT vp."corporateNumber", s.id, s."systemName"
+FROM public."vendorProject" vp
+JOIN public."projectSystemRelation" psr ON psr."projectId" = vp."projectId"
+JOIN public.system s ON s.id = psr."systemId"
+WHERE (CASE WHEN $1::text != '' THEN vp."corporateNumber" = $1::text ELSE TRUE END)
+ORDER BY vp."corporateNumber", s."systemName"
+`
+
+type ListVendorSystemsRow struct {
+	CorporateNumber string    `json:"corporateNumber"`
+	ID              uuid.UUID `json:"id"`
+	SystemName      string    `json:"systemName"`
+}
+
+// ベンダーのプロジェクトに紐づくシステム（複数プロジェクトで重複するものは1件にまとめる）
+func (q *Queries) ListVendorSystems(ctx context.Context, corporateNumber string) ([]ListVendorSystemsRow, error) {
+	rows, err := q.db.Query(ctx, listVendorSystems, corporateNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVendorSystemsRow
+	for rows.Next() {
+		var i ListVendorSystemsRow
+		if err := rows.Scan(&i.CorporateNumber, &i.ID, &i.SystemName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVendors = `-- name: ListVendors :many
+SELECT "corporateNumber", "vendorName", "vendorNameKana", "prefectureName", "cityName",
+       "streetNumber", "postCode", "closeDate", "ntaUpdatedAt"
+FROM public."vendorDirectory"
+WHERE (CASE WHEN $1::text != '' THEN "corporateNumber" = $1::text ELSE TRUE END)
+ORDER BY "corporateNumber"
+`
+
+func (q *Queries) ListVendors(ctx context.Context, corporateNumber string) ([]VendorDirectory, error) {
+	rows, err := q.db.Query(ctx, listVendors, corporateNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VendorDirectory
+	for rows.Next() {
+		var i VendorDirectory
+		if err := rows.Scan(
+			&i.CorporateNumber,
+			&i.VendorName,
+			&i.VendorNameKana,
+			&i.PrefectureName,
+			&i.CityName,
+			&i.StreetNumber,
+			&i.PostCode,
+			&i.CloseDate,
+			&i.NtaUpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertVendor = `-- name: UpsertVendor :exec
+INSERT INTO public.vendor (
+  "corporateNumber", "vendorName", "vendorNameKana", "prefectureName", "cityName",
+  "streetNumber", "postCode", "closeDate", "ntaUpdatedAt"
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT ("corporateNumber") DO UPDATE SET
+  "vendorName" = EXCLUDED."vendorName",
+  "vendorNameKana" = EXCLUDED."vendorNameKana",
+  "prefectureName" = EXCLUDED."prefectureName",
+  "cityName" = EXCLUDED."cityName",
+  "streetNumber" = EXCLUDED."streetNumber",
+  "postCode" = EXCLUDED."postCode",
+  "closeDate" = EXCLUDED."closeDate",
+  "ntaUpdatedAt" = EXCLUDED."ntaUpdatedAt",
+  "updatedAt" = now()
+WHERE public.vendor."ntaUpdatedAt" <= EXCLUDED."ntaUpdatedAt"
+`
+
+type UpsertVendorParams struct {
+	CorporateNumber string     `json:"corporateNumber"`
+	VendorName      string     `json:"vendorName"`
+	VendorNameKana  *string    `json:"vendorNameKana"`
+	PrefectureName  *string    `json:"prefectureName"`
+	CityName        *string    `json:"cityName"`
+	StreetNumber    *string    `json:"streetNumber"`
+	PostCode        *string    `json:"postCode"`
+	CloseDate       *time.Time `json:"closeDate"`
+	NtaUpdatedAt    time.Time  `json:"ntaUpdatedAt"`
+}
+
+// 法人番号公表サイトの更新日が新しい場合のみ上書きする
+func (q *Queries) UpsertVendor(ctx context.Context, arg UpsertVendorParams) error {
+	_, err := q.db.Exec(ctx, upsertVendor,
+		arg.CorporateNumber,
+		arg.VendorName,
+		arg.VendorNameKana,
+		arg.PrefectureName,
+		arg.CityName,
+		arg.StreetNumber,
+		arg.PostCode,
+		arg.CloseDate,
+		arg.NtaUpdatedAt,
+	)
+	return err
+}
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS public."system_searchText_trgm_idx";
+
+ALTER TABLE IF EXISTS public.system DROP COLUMN IF EXISTS "searchText";
+ALTER TABLE IF EXISTS public.system DROP COLUMN IF EXISTS "systemNameKana";
+
+DROP FUNCTION IF EXISTS public.normalize_search_text(text);
+
+DROP EXTENSION IF EXISTS pg_trgm;
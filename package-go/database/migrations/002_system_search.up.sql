@@ -0,0 +1,37 @@
+--
+-- システム名の日本語あいまい検索
+--
+-- 全角・半角（NFKC）とカタカナ・ひらがなの揺れを吸収した検索用カラムを
+-- 生成列として持ち、pg_trgm のGINインデックスで部分一致・類似度検索を行う。
+-- 漢字の読みで検索できるよう、読み仮名（systemNameKana）も検索対象に含める。
+--
+-- 既存の行の読み仮名はここでは埋めない（漢字から読みを機械的に求められないため）。
+-- 初期データのシステムは 006 マイグレーションで補完し、それ以外はシステムの更新・一括取り込み（systemNameKana 列）で登録する。
+--
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+--
+-- Name: normalize_search_text; Type: FUNCTION; Schema: public; Owner: -
+-- NFKC正規化後にカタカナをひらがなへ寄せ、小文字化する（package-go/search.Normalize と同じ規則）
+--
+
+CREATE OR REPLACE FUNCTION public.normalize_search_text(input text) RETURNS text
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+    AS $$
+    SELECT lower(translate(normalize(input, NFKC),
+        'ァアィイゥウェエォオカガキギクグケゲコゴサザシジスズセゼソゾタダチヂッツヅテデトドナニヌネノハバパヒビピフブプヘベペホボポマミムメモャヤュユョヨラリルレロヮワヰヱヲンヴヵヶ',
+        'ぁあぃいぅうぇえぉおかがきぎくぐけげこごさざしじすずせぜそぞただちぢっつづてでとどなにぬねのはばぱひびぴふぶぷへべぺほぼぽまみむめもゃやゅゆょよらりるれろゎわゐゑをんゔゕゖ'))
+$$;
+
+ALTER TABLE public.system ADD COLUMN "systemNameKana" character varying(255);
+
+ALTER TABLE public.system ADD COLUMN "searchText" text GENERATED ALWAYS AS (
+    public.normalize_search_text(("systemName")::text || ' ' || COALESCE(("systemNameKana")::text, ''))
+) STORED NOT NULL;
+
+--
+-- Name: system_searchText_trgm_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX "system_searchText_trgm_idx" ON public.system USING gin ("searchText" public.gin_trgm_ops);
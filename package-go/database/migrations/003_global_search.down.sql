@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS public."m_localGovernment_searchText_trgm_idx";
+DROP INDEX IF EXISTS public."gcasGroup_groupName_trgm_idx";
+DROP INDEX IF EXISTS public."project_corporateNumber_idx";
+DROP INDEX IF EXISTS public."project_searchText_trgm_idx";
+DROP INDEX IF EXISTS public."system_remark_trgm_idx";
@@ -0,0 +1,37 @@
+--
+-- 横断検索（GET /api/v1/search）用のインデックス
+--
+-- 002 で作成した public.normalize_search_text を式インデックスに使い、
+-- システムの備考、プロジェクト名・ベンダー名、グループ名、自治体名・読み仮名を
+-- 表記揺れを吸収した部分一致・類似度検索の対象にする。
+--
+
+--
+-- Name: system_remark_trgm_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX "system_remark_trgm_idx" ON public.system USING gin (public.normalize_search_text((remark)::text) public.gin_trgm_ops);
+
+--
+-- Name: project_searchText_trgm_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX "project_searchText_trgm_idx" ON public.project USING gin (public.normalize_search_text((("projectName")::text || ' ' || ("vendorName")::text)) public.gin_trgm_ops);
+
+--
+-- Name: project_corporateNumber_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX "project_corporateNumber_idx" ON public.project USING btree ("corporateNumber" varchar_pattern_ops);
+
+--
+-- Name: gcasGroup_groupName_trgm_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX "gcasGroup_groupName_trgm_idx" ON public."gcasGroup" USING gin (public.normalize_search_text(("groupName")::text) public.gin_trgm_ops);
+
+--
+-- Name: m_localGovernment_searchText_trgm_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX "m_localGovernment_searchText_trgm_idx" ON public."m_localGovernment" USING gin (public.normalize_search_text((("cityName")::text || ' ' || ("cityNameKana")::text)) public.gin_trgm_ops);
@@ -0,0 +1,9 @@
+DROP VIEW IF EXISTS public."vendorDirectory";
+DROP VIEW IF EXISTS public."vendorProject";
+
+DROP TABLE IF EXISTS public.vendor;
+
+ALTER TABLE IF EXISTS public."systemBasicInformation" DROP CONSTRAINT IF EXISTS "systemBasicInformation_corporateNumber_check";
+ALTER TABLE IF EXISTS public.project DROP CONSTRAINT IF EXISTS "project_corporateNumber_check";
+
+DROP FUNCTION IF EXISTS public.is_valid_corporate_number(text);
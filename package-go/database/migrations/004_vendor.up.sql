@@ -0,0 +1,102 @@
+--
+-- 法人番号のチェックデジット検証とベンダー台帳
+--
+-- project / systemBasicInformation の法人番号を書き込み時に検証し、
+-- 国税庁の法人番号公表サイトのデータで補完するベンダーテーブルと、
+-- 各テーブルに重複して持っているベンダー名を法人番号単位にまとめるビューを作成する。
+--
+
+--
+-- Name: is_valid_corporate_number; Type: FUNCTION; Schema: public; Owner: -
+-- 13桁の数字で、先頭のチェックデジットが正しいか（package-go/corporatenumber.Validate と同じ規則）
+--
+
+CREATE OR REPLACE FUNCTION public.is_valid_corporate_number(input text) RETURNS boolean
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+    AS $$
+    SELECT CASE WHEN input ~ '^[0-9]{13}$' THEN
+        substr(input, 1, 1)::int = 9 - (
+            SELECT sum(substr(input, 14 - n, 1)::int * (2 - n % 2))::int
+            FROM generate_series(1, 12) AS n
+        ) % 9
+    ELSE false END
+$$;
+
+--
+-- 既存データは検証せず（NOT VALID）、以降の INSERT / UPDATE のみを検証する
+--
+
+ALTER TABLE public.project
+    ADD CONSTRAINT "project_corporateNumber_check" CHECK (public.is_valid_corporate_number(("corporateNumber")::text)) NOT VALID;
+
+ALTER TABLE public."systemBasicInformation"
+    ADD CONSTRAINT "systemBasicInformation_corporateNumber_check" CHECK (public.is_valid_corporate_number(("corporateNumber")::text)) NOT VALID;
+
+--
+-- Name: vendor; Type: TABLE; Schema: public; Owner: -
+-- 法人番号公表サイトの基本3情報（商号又は名称・所在地・法人番号）
+--
+
+CREATE TABLE public.vendor (
+    "corporateNumber" character varying(13) NOT NULL,
+    "vendorName" character varying(255) NOT NULL,
+    "vendorNameKana" character varying(255),
+    "prefectureName" character varying(10),
+    "cityName" character varying(255),
+    "streetNumber" character varying(600),
+    "postCode" character varying(7),
+    "closeDate" date,
+    "ntaUpdatedAt" date NOT NULL,
+    "createdAt" timestamp with time zone DEFAULT now() NOT NULL,
+    "updatedAt" timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT "vendor_corporateNumber_check" CHECK (public.is_valid_corporate_number(("corporateNumber")::text))
+);
+
+--
+-- Name: vendor vendor_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.vendor
+    ADD CONSTRAINT vendor_pkey PRIMARY KEY ("corporateNumber");
+
+--
+-- Name: vendorProject; Type: VIEW; Schema: public; Owner: -
+-- 法人番号とプロジェクトの対応（プロジェクト本体またはシステム基本情報に記載されたもの）
+--
+
+CREATE VIEW public."vendorProject" AS
+ SELECT project."corporateNumber",
+    project.id AS "projectId"
+   FROM public.project
+UNION
+ SELECT "systemBasicInformation"."corporateNumber",
+    "systemBasicInformation"."projectId"
+   FROM public."systemBasicInformation";
+
+--
+-- Name: vendorDirectory; Type: VIEW; Schema: public; Owner: -
+-- 法人番号ごとのベンダー。名称は vendor テーブルを優先し、なければ最後に更新された記載を使う
+--
+
+CREATE VIEW public."vendorDirectory" AS
+ SELECT refs."corporateNumber",
+    COALESCE(v."vendorName", refs."vendorName") AS "vendorName",
+    v."vendorNameKana",
+    v."prefectureName",
+    v."cityName",
+    v."streetNumber",
+    v."postCode",
+    v."closeDate",
+    v."ntaUpdatedAt"
+   FROM (
+     SELECT DISTINCT ON (r."corporateNumber") r."corporateNumber", r."vendorName"
+       FROM (
+         SELECT project."corporateNumber", project."vendorName", project."updatedAt"
+           FROM public.project
+         UNION ALL
+         SELECT "systemBasicInformation"."corporateNumber", "systemBasicInformation"."vendorName", "systemBasicInformation"."updatedAt"
+           FROM public."systemBasicInformation"
+       ) r
+      ORDER BY r."corporateNumber", r."updatedAt" DESC
+   ) refs
+   LEFT JOIN public.vendor v ON v."corporateNumber" = refs."corporateNumber";
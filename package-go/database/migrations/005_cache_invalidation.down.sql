@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS "m_userRole_cache_invalidation" ON public."m_userRole";
+DROP TRIGGER IF EXISTS "m_organizationCategory_cache_invalidation" ON public."m_organizationCategory";
+DROP TRIGGER IF EXISTS "m_localGovernment_cache_invalidation" ON public."m_localGovernment";
+DROP TRIGGER IF EXISTS system_cache_invalidation ON public.system;
+
+DROP FUNCTION IF EXISTS public.notify_cache_invalidation();
@@ -0,0 +1,36 @@
+--
+-- アプリケーションのキャッシュを破棄するための書き込み通知
+--
+-- キャッシュ対象のテーブルへの書き込み（INSERT / UPDATE / DELETE / TRUNCATE）を
+-- cache_invalidation チャネルにテーブル名で通知する（package-go/database.ListenCacheInvalidation）。
+-- 文単位のトリガーのため、一括取り込みでも通知は文ごとに 1 回で、コミットされた場合のみ届く。
+--
+
+--
+-- Name: notify_cache_invalidation; Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE OR REPLACE FUNCTION public.notify_cache_invalidation() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    PERFORM pg_notify('cache_invalidation', TG_TABLE_NAME);
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER system_cache_invalidation
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public.system
+    FOR EACH STATEMENT EXECUTE FUNCTION public.notify_cache_invalidation();
+
+CREATE TRIGGER "m_localGovernment_cache_invalidation"
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public."m_localGovernment"
+    FOR EACH STATEMENT EXECUTE FUNCTION public.notify_cache_invalidation();
+
+CREATE TRIGGER "m_organizationCategory_cache_invalidation"
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public."m_organizationCategory"
+    FOR EACH STATEMENT EXECUTE FUNCTION public.notify_cache_invalidation();
+
+CREATE TRIGGER "m_userRole_cache_invalidation"
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public."m_userRole"
+    FOR EACH STATEMENT EXECUTE FUNCTION public.notify_cache_invalidation();
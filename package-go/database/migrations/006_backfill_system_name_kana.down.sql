@@ -0,0 +1,16 @@
+-- 補完した読み仮名を NULL に戻す（同じ読みを更新・一括取り込みで登録した行も戻る）
+
+UPDATE public.system AS s
+SET "systemNameKana" = NULL
+FROM (VALUES
+    ('住民基本台帳システム', 'ジュウミンキホンダイチョウシステム'),
+    ('税務管理システム', 'ゼイムカンリシステム'),
+    ('健康管理システム', 'ケンコウカンリシステム'),
+    ('介護保険システム', 'カイゴホケンシステム'),
+    ('教育情報システム', 'キョウイクジョウホウシステム'),
+    ('共通基盤システム', 'キョウツウキバンシステム'),
+    ('災害対応システム', 'サイガイタイオウシステム'),
+    ('図書館管理システム', 'トショカンカンリシステム')
+) AS v(name, kana)
+WHERE s."systemName" = v.name
+  AND s."systemNameKana" = v.kana;
@@ -0,0 +1,23 @@
+--
+-- 既存のシステムの読み仮名の補完
+--
+-- 002 マイグレーションより前に登録した行は読み仮名（systemNameKana）が NULL のため、
+-- 「ｼﾞｭｳﾐﾝ」のようなかなの検索語が漢字のシステム名にヒットしない。
+-- 初期データ（package-go/database/seed）のシステムは読みが分かっているため、ここで埋める。
+-- それ以外の行はシステムの更新・一括取り込み（systemNameKana 列）で登録する。
+--
+
+UPDATE public.system AS s
+SET "systemNameKana" = v.kana
+FROM (VALUES
+    ('住民基本台帳システム', 'ジュウミンキホンダイチョウシステム'),
+    ('税務管理システム', 'ゼイムカンリシステム'),
+    ('健康管理システム', 'ケンコウカンリシステム'),
+    ('介護保険システム', 'カイゴホケンシステム'),
+    ('教育情報システム', 'キョウイクジョウホウシステム'),
+    ('共通基盤システム', 'キョウツウキバンシステム'),
+    ('災害対応システム', 'サイガイタイオウシステム'),
+    ('図書館管理システム', 'トショカンカンリシステム')
+) AS v(name, kana)
+WHERE s."systemName" = v.name
+  AND s."systemNameKana" IS NULL;
@@ -1,54 +1,89 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/config"
+	"sample-micro-service-api/package-go/logging"
 )
 
+// migrationFiles はバイナリに埋め込んだマイグレーションファイル（作業ディレクトリに依存しない）
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey はマイグレーションの実行を直列化するアドバイザリロックのキー
+// golang-migrateが操作ごとに取るロックとは別のキーにし、status→up のような一連の操作をまとめて守る
+const migrationLockKey int64 = 0x6d6967726174650a
+
+// migrationLockRetry はロックを待つ間に再試行する間隔
+const migrationLockRetry = time.Second
+
+// migrationName は create で作成するマイグレーションの名前として許す形式
+var migrationName = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// ErrMigrationLocked は他のプロセスがマイグレーションを実行中でロックを取れなかった場合のエラー
+var ErrMigrationLocked = errors.New("another migration is in progress")
+
 type Migrator struct {
-	db        *sql.DB
-	migrate   *migrate.Migrate
-	sourceURL string
+	db      *sql.DB
+	migrate *migrate.Migrate
+}
+
+// MigrationStatus はDBに適用済みのマイグレーションと、埋め込んだマイグレーションとの差分
+type MigrationStatus struct {
+	Version uint   // 適用済みのバージョン（未適用の場合は0）
+	Dirty   bool   // 前回のマイグレーションが途中で失敗したか
+	Latest  uint   // 埋め込んだマイグレーションの最新バージョン
+	Pending []uint // 未適用のバージョン
 }
 
 // NewMigrator creates a new migrator instance
+// マイグレーションはバイナリに埋め込んだファイルを使う。Closeでdbも閉じる
 func NewMigrator(db *sql.DB) (*Migrator, error) {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create migrate driver: %w", err)
 	}
 
-	migrationDir := os.Getenv("MIGRATION_DIR")
-	if migrationDir == "" {
-		migrationDir = "migrations"
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
 	}
 
-	sourceURL := "file://" + migrationDir
-	m, err := migrate.NewWithDatabaseInstance(
-		sourceURL,
-		"postgres",
-		driver,
-	)
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
 	}
 
 	return &Migrator{
-		db:        db,
-		migrate:   m,
-		sourceURL: sourceURL,
+		db:      db,
+		migrate: m,
 	}, nil
 }
 
 // Up runs all available migrations
 func (m *Migrator) Up() error {
 	if err := m.migrate.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations up: %w", err)
+		return migrationError("failed to run migrations up", err)
 	}
 	return nil
 }
@@ -56,7 +91,7 @@ func (m *Migrator) Up() error {
 // Down reverts all migrations
 func (m *Migrator) Down() error {
 	if err := m.migrate.Down(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations down: %w", err)
+		return migrationError("failed to run migrations down", err)
 	}
 	return nil
 }
@@ -64,7 +99,24 @@ func (m *Migrator) Down() error {
 // Steps runs n migration steps (positive for up, negative for down)
 func (m *Migrator) Steps(n int) error {
 	if err := m.migrate.Steps(n); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run %d migration steps: %w", n, err)
+		return migrationError(fmt.Sprintf("failed to run %d migration steps", n), err)
+	}
+	return nil
+}
+
+// Goto はversionまで（現在より新しければ適用、古ければ巻き戻し）マイグレーションを実行する
+func (m *Migrator) Goto(version uint) error {
+	if err := m.migrate.Migrate(version); err != nil && err != migrate.ErrNoChange {
+		return migrationError(fmt.Sprintf("failed to migrate to version %d", version), err)
+	}
+	return nil
+}
+
+// Force はマイグレーションを実行せずにバージョンをversionにし、dirtyを解除する
+// 失敗したマイグレーションを手で直した後に使う（-1で未適用の状態にする）
+func (m *Migrator) Force(version int) error {
+	if err := m.migrate.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version %d: %w", version, err)
 	}
 	return nil
 }
@@ -74,6 +126,65 @@ func (m *Migrator) Version() (uint, bool, error) {
 	return m.migrate.Version()
 }
 
+// Status は適用済みのバージョンと未適用のマイグレーションを返す
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	version, dirty, err := m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	versions, err := migrationVersions(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{Version: version, Dirty: dirty}
+	for _, v := range versions {
+		if v > version {
+			status.Pending = append(status.Pending, v)
+		}
+		if v > status.Latest {
+			status.Latest = v
+		}
+	}
+	return status, nil
+}
+
+// Lock はマイグレーション用のアドバイザリロックを取り、解放する関数を返す
+// 他のプロセスがロックを持っている間はctxが終わるまで待つ（待ち切れない場合はErrMigrationLocked）
+func (m *Migrator) Lock(ctx context.Context) (func() error, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection for migration lock: %w", err)
+	}
+
+	for {
+		var locked bool
+		if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&locked); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if locked {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return nil, fmt.Errorf("%w: %v", ErrMigrationLocked, ctx.Err())
+		case <-time.After(migrationLockRetry):
+		}
+	}
+
+	return func() error {
+		defer conn.Close()
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			return fmt.Errorf("failed to release migration lock: %w", err)
+		}
+		return nil
+	}, nil
+}
+
 // Close closes the migrator
 func (m *Migrator) Close() error {
 	sourceErr, dbErr := m.migrate.Close()
@@ -81,4 +192,143 @@ func (m *Migrator) Close() error {
 		return sourceErr
 	}
 	return dbErr
-} 
\ No newline at end of file
+}
+
+// PrepareSchema は起動時にDBのスキーマを確認する
+// AUTO_MIGRATEの場合はアドバイザリロックを取って未適用のマイグレーションを適用し（他のインスタンスが適用中であれば完了を待つ）、
+// MIGRATION_STRICTの場合はDBのバージョンが埋め込んだマイグレーションより古いか、dirtyであればエラーを返す
+func (c *Client) PrepareSchema(ctx context.Context, cfg config.DatabaseConfig) error {
+	if !cfg.AutoMigrate && !cfg.MigrationStrict {
+		return nil
+	}
+
+	// Migrator.Closeは渡したsql.DBも閉じるため、Client.DBとは別のアダプタを使う
+	migrator, err := NewMigrator(stdlib.OpenDBFromPool(c.Pool))
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	logger := logging.Named(logging.GetLogger(), logging.LoggerDatabase)
+	if cfg.AutoMigrate {
+		lockCtx, cancel := context.WithTimeout(ctx, cfg.MigrationLockTimeout)
+		unlock, err := migrator.Lock(lockCtx)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		err = migrator.Up()
+		if unlockErr := unlock(); unlockErr != nil {
+			logger.Warn("Failed to release migration lock", zap.Error(unlockErr))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	logger.Info("Database schema checked",
+		zap.Uint("version", status.Version),
+		zap.Uint("latest", status.Latest),
+		zap.Bool("dirty", status.Dirty),
+		zap.Bool("autoMigrate", cfg.AutoMigrate),
+	)
+
+	if cfg.MigrationStrict {
+		if status.Dirty {
+			return fmt.Errorf("database is dirty at migration version %d; fix the schema by hand and run `force` before starting", status.Version)
+		}
+		if len(status.Pending) > 0 {
+			return fmt.Errorf("database is at migration version %d, behind the latest %d; run `up` or set AUTO_MIGRATE=true", status.Version, status.Latest)
+		}
+	}
+	return nil
+}
+
+// migrationError は前回のマイグレーションが途中で失敗している（dirty）場合に対処方法を添えてエラーを返す
+func migrationError(msg string, err error) error {
+	var dirty migrate.ErrDirty
+	if errors.As(err, &dirty) {
+		previous := dirty.Version - 1
+		if dirty.Version <= 1 {
+			previous = -1
+		}
+		return fmt.Errorf("%s: database is dirty at version %d; fix the schema by hand, then run `force %d` (or `force %d` if the migration did not apply): %w",
+			msg, dirty.Version, dirty.Version, previous, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// LatestMigrationVersion は埋め込んだマイグレーションファイル（{version}_{title}.up.sql）の最大バージョンを返す
+func LatestMigrationVersion() (uint, error) {
+	versions, err := migrationVersions(migrationFiles, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+// CreateMigration はdirに次のバージョンのマイグレーションファイル（up・down）をup・downの内容で作成し、そのパスを返す
+func CreateMigration(dir, name, up, down string) ([]string, error) {
+	if !migrationName.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q: use lowercase letters, digits and underscores", name)
+	}
+
+	versions, err := migrationVersions(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, err
+	}
+	next := versions[len(versions)-1] + 1
+
+	var paths []string
+	for _, file := range []struct{ direction, content string }{{"up", up}, {"down", down}} {
+		path := filepath.Join(dir, fmt.Sprintf("%03d_%s.%s.sql", next, name, file.direction))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return paths, fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		_, err = f.WriteString(file.content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return paths, fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// migrationVersions はfsysのdir内のマイグレーションファイル（{version}_{title}.up.sql）のバージョンを昇順で返す
+func migrationVersions(fsys fs.FS, dir string) ([]uint, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration directory: %w", err)
+	}
+
+	var versions []uint
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, uint(version))
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no migrations found in %s", dir)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, nil
+}
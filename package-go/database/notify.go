@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/logging"
+)
+
+// CacheInvalidationChannel はテーブルの書き込みを通知するチャネル（migrations/005_cache_invalidation のトリガーが送信する）
+const CacheInvalidationChannel = "cache_invalidation"
+
+// listenerPingInterval は通知がない間に接続を確認する間隔
+const listenerPingInterval = 90 * time.Second
+
+// 切断後の再接続の間隔（指数的に伸ばし、maxListenerBackoffで頭打ちにする）
+const (
+	initialListenerBackoff = time.Second
+	maxListenerBackoff     = time.Minute
+)
+
+// ListenCacheInvalidation はLISTEN/NOTIFYで他のインスタンスの書き込みを受け取り、キャッシュを破棄する
+// プールとは別の専用の接続を使う。再接続した場合は切断中の通知を取りこぼしている可能性があるため、すべてのキャッシュを破棄する。
+// 戻り値の関数で受信を止める。キャッシュが無効な場合は何もしない。
+func (c *Client) ListenCacheInvalidation() (func() error, error) {
+	if c.cache == nil {
+		return func() error { return nil }, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn, err := c.listen(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	logger := logging.Named(logging.GetLogger(), logging.LoggerDatabase)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			err := c.receiveNotifications(ctx, conn, logger)
+			conn.Close(context.Background())
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn("Cache invalidation listener error", zap.Error(err))
+
+			backoff := initialListenerBackoff
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+				if conn, err = c.listen(ctx); err == nil {
+					break
+				}
+				logger.Warn("Failed to reconnect cache invalidation listener", zap.Duration("retryIn", backoff), zap.Error(err))
+				backoff = min(backoff*2, maxListenerBackoff)
+			}
+
+			logger.Info("Cache invalidation listener reconnected, purging caches")
+			c.cache.Invalidate("")
+		}
+	}()
+
+	return func() error {
+		cancel()
+		<-done
+		return nil
+	}, nil
+}
+
+// listen は専用の接続を開き、CacheInvalidationChannelをLISTENする
+func (c *Client) listen(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.ConnectConfig(ctx, c.Pool.Config().ConnConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect cache invalidation listener: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{CacheInvalidationChannel}.Sanitize()); err != nil {
+		conn.Close(context.Background())
+		return nil, fmt.Errorf("failed to listen on %s: %w", CacheInvalidationChannel, err)
+	}
+	return conn, nil
+}
+
+// receiveNotifications は接続が切れる（またはctxが終了する）まで通知を受け取り、テーブルのキャッシュを破棄する
+// listenerPingIntervalの間通知がなければPingで接続を確認する
+func (c *Client) receiveNotifications(ctx context.Context, conn *pgx.Conn, logger *zap.Logger) error {
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, listenerPingInterval)
+		notification, err := conn.WaitForNotification(waitCtx)
+		cancel()
+
+		switch {
+		case err == nil:
+			logger.Debug("Invalidating cache", zap.String("table", notification.Payload))
+			c.cache.Invalidate(notification.Payload)
+		case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+			if err := conn.Ping(ctx); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package nta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+
+	"sample-micro-service-api/package-go/corporatenumber"
+	"sample-micro-service-api/package-go/database/internal/db"
+)
+
+// maxReportedInvalid はReportに保持する不正行の上限
+const maxReportedInvalid = 100
+
+// Options は取り込み時のオプション
+type Options struct {
+	All    bool // trueの場合はプロジェクト等に記載のない法人も取り込む
+	DryRun bool // trueの場合は集計のみを行い、DBへは反映しない
+}
+
+// InvalidRecord は法人番号の検証に失敗した行
+type InvalidRecord struct {
+	Line            int
+	CorporateNumber string
+	Err             error
+}
+
+// Report は取り込み全体の結果
+type Report struct {
+	DryRun   bool
+	Read     int // 読み込んだ行数
+	Upserted int // vendorテーブルへ反映した（DryRunでは反映対象の）行数
+	Skipped  int // 最新履歴でない・削除済み・対象外の行数
+	Invalid  int // 法人番号が不正な行数
+	Invalids []InvalidRecord
+}
+
+// TxBeginner はトランザクションを開始する（pgxpool.Pool）
+// Readerを読み進めながら書き込むため再試行できず、database.Client.InTxは使わない
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Enricher は法人番号公表サイトのデータでvendorテーブルを補完する
+type Enricher struct {
+	database TxBeginner
+}
+
+// NewEnricher はEnricherの新しいインスタンスを作成
+func NewEnricher(database TxBeginner) *Enricher {
+	return &Enricher{database: database}
+}
+
+// Enrich はReaderの全行を読み込み、1トランザクションでvendorテーブルへ反映する
+// 既定ではプロジェクト・システム基本情報に記載された法人番号のみを対象にする
+func (e *Enricher) Enrich(ctx context.Context, reader *Reader, opts Options) (*Report, error) {
+	tx, err := e.database.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	queries := db.New(tx)
+
+	var targets map[string]struct{}
+	if !opts.All {
+		numbers, err := queries.ListReferencedCorporateNumbers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list referenced corporate numbers: %w", err)
+		}
+		targets = make(map[string]struct{}, len(numbers))
+		for _, number := range numbers {
+			targets[number] = struct{}{}
+		}
+	}
+
+	report := &Report{DryRun: opts.DryRun}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.Read++
+
+		if err := corporatenumber.Validate(record.CorporateNumber); err != nil {
+			report.Invalid++
+			if len(report.Invalids) < maxReportedInvalid {
+				report.Invalids = append(report.Invalids, InvalidRecord{
+					Line:            record.Line,
+					CorporateNumber: record.CorporateNumber,
+					Err:             err,
+				})
+			}
+			continue
+		}
+
+		if !record.Latest || record.Process == ProcessDeleted {
+			report.Skipped++
+			continue
+		}
+		if targets != nil {
+			if _, ok := targets[record.CorporateNumber]; !ok {
+				report.Skipped++
+				continue
+			}
+		}
+
+		report.Upserted++
+		if opts.DryRun {
+			continue
+		}
+		if err := queries.UpsertVendor(ctx, toUpsertParams(record)); err != nil {
+			return nil, fmt.Errorf("failed to upsert vendor at line %d: %w", record.Line, err)
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit vendors: %w", err)
+	}
+	return report, nil
+}
+
+func toUpsertParams(record *Record) db.UpsertVendorParams {
+	return db.UpsertVendorParams{
+		CorporateNumber: record.CorporateNumber,
+		VendorName:      record.Name,
+		VendorNameKana:  emptyToNil(record.Furigana),
+		PrefectureName:  emptyToNil(record.PrefectureName),
+		CityName:        emptyToNil(record.CityName),
+		StreetNumber:    emptyToNil(record.StreetNumber),
+		PostCode:        emptyToNil(record.PostCode),
+		NtaUpdatedAt:    record.UpdateDate,
+		CloseDate:       record.CloseDate,
+	}
+}
+
+// emptyToNil は空文字をNULLとして扱う
+func emptyToNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
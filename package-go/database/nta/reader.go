@@ -0,0 +1,241 @@
+// Package nta は国税庁 法人番号公表サイトの全件・差分データ（CSV形式）を読み込む
+package nta
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+
+	"sample-micro-service-api/package-go/database/importer"
+)
+
+// ErrInvalidFile はファイル形式・文字コードが不正な場合のエラー
+var ErrInvalidFile = errors.New("invalid NTA corporate number file")
+
+// CSV形式（Unicode / Shift_JIS）の列位置（ヘッダー行なし、30列）
+const (
+	colSequenceNumber = iota
+	colCorporateNumber
+	colProcess
+	colCorrect
+	colUpdateDate
+	colChangeDate
+	colName
+	colNameImageID
+	colKind
+	colPrefectureName
+	colCityName
+	colStreetNumber
+	colAddressImageID
+	colPrefectureCode
+	colCityCode
+	colPostCode
+	colAddressOutside
+	colAddressOutsideImageID
+	colCloseDate
+	colCloseCause
+	colSuccessorCorporateNumber
+	colChangeCause
+	colAssignmentDate
+	colLatest
+	colEnName
+	colEnPrefectureName
+	colEnCityName
+	colEnAddressOutside
+	colFurigana
+	colHihyoji
+	columnCount
+)
+
+// ProcessDeleted は処理区分「削除」
+const ProcessDeleted = "99"
+
+const dateLayout = "2006-01-02"
+
+// Record は法人番号公表サイトの1行分の基本3情報
+type Record struct {
+	Line            int
+	CorporateNumber string
+	Process         string // 処理区分（01: 新規、11: 商号又は名称の変更、21: 登記記録の閉鎖等、99: 削除 など）
+	UpdateDate      time.Time
+	Name            string
+	Furigana        string
+	PrefectureName  string
+	CityName        string
+	StreetNumber    string
+	PostCode        string
+	CloseDate       *time.Time
+	Latest          bool // 最新履歴かどうか
+}
+
+// Reader は1行ずつレコードを読み出す
+// 全件データは数百万行になるため、ファイル全体をメモリに載せない
+type Reader struct {
+	csv    *csv.Reader
+	closer io.Closer
+	line   int
+}
+
+// Open はCSVファイル、またはCSVを含むZIPファイル（ダウンロードしたままの形式）を開く
+func Open(path string, encoding importer.Encoding) (*Reader, error) {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		return openZip(path, encoding)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	reader, err := NewReader(file, encoding)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	reader.closer = file
+	return reader, nil
+}
+
+func openZip(path string, encoding importer.Encoding) (*Reader, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	for _, entry := range archive.File {
+		if !strings.EqualFold(filepath.Ext(entry.Name), ".csv") {
+			continue
+		}
+		file, err := entry.Open()
+		if err != nil {
+			archive.Close()
+			return nil, fmt.Errorf("failed to open %s in %s: %w", entry.Name, path, err)
+		}
+		reader, err := NewReader(file, encoding)
+		if err != nil {
+			file.Close()
+			archive.Close()
+			return nil, err
+		}
+		reader.closer = multiCloser{file, archive}
+		return reader, nil
+	}
+
+	archive.Close()
+	return nil, fmt.Errorf("%w: %s contains no .csv file", ErrInvalidFile, path)
+}
+
+// NewReader はCSVを読み込むReaderを作成
+// EncodingAutoの場合は先頭部分がUTF-8として正しければUnicode版、そうでなければShift_JIS版とみなす
+func NewReader(r io.Reader, encoding importer.Encoding) (*Reader, error) {
+	buffered := bufio.NewReaderSize(r, 64<<10)
+
+	head, err := buffered.Peek(64 << 10)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if bytes.HasPrefix(head, []byte{0xEF, 0xBB, 0xBF}) {
+		buffered.Discard(3)
+		head = head[3:]
+	}
+
+	if encoding == importer.EncodingAuto {
+		// 読み込み途中で途切れた末尾の行は判定に含めない
+		if i := bytes.LastIndexByte(head, '\n'); i >= 0 {
+			head = head[:i]
+		}
+		if utf8.Valid(head) {
+			encoding = importer.EncodingUTF8
+		} else {
+			encoding = importer.EncodingShiftJIS
+		}
+	}
+
+	var decoded io.Reader = buffered
+	if encoding == importer.EncodingShiftJIS {
+		decoded = japanese.ShiftJIS.NewDecoder().Reader(buffered)
+	}
+
+	reader := csv.NewReader(decoded)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	return &Reader{csv: reader}, nil
+}
+
+// Read は次のレコードを返す。終端ではio.EOFを返す
+func (r *Reader) Read() (*Record, error) {
+	fields, err := r.csv.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	r.line++
+
+	if len(fields) < colLatest+1 {
+		return nil, fmt.Errorf("%w: line %d has %d columns (expected %d)", ErrInvalidFile, r.line, len(fields), columnCount)
+	}
+
+	updateDate, err := time.Parse(dateLayout, fields[colUpdateDate])
+	if err != nil {
+		return nil, fmt.Errorf("%w: line %d has an invalid update date %q", ErrInvalidFile, r.line, fields[colUpdateDate])
+	}
+
+	record := &Record{
+		Line:            r.line,
+		CorporateNumber: fields[colCorporateNumber],
+		Process:         fields[colProcess],
+		UpdateDate:      updateDate,
+		Name:            fields[colName],
+		PrefectureName:  fields[colPrefectureName],
+		CityName:        fields[colCityName],
+		StreetNumber:    fields[colStreetNumber],
+		PostCode:        fields[colPostCode],
+		Latest:          fields[colLatest] == "1",
+	}
+	if len(fields) > colFurigana {
+		record.Furigana = fields[colFurigana]
+	}
+	if fields[colCloseDate] != "" {
+		closeDate, err := time.Parse(dateLayout, fields[colCloseDate])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d has an invalid close date %q", ErrInvalidFile, r.line, fields[colCloseDate])
+		}
+		record.CloseDate = &closeDate
+	}
+
+	return record, nil
+}
+
+// Close は開いたファイルを閉じる
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+type multiCloser []io.Closer
+
+func (m multiCloser) Close() error {
+	var errs []error
+	for _, c := range m {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
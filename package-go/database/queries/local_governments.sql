@@ -0,0 +1,13 @@
+-- name: GetLocalGovernment :one
+SELECT id, "prefectureName", "cityName", "prefectureNameKana", "cityNameKana",
+       "createdAt", "updatedAt"
+FROM public."m_localGovernment"
+WHERE id = $1 LIMIT 1;
+
+-- name: FindLocalGovernmentsByName :many
+SELECT id, "prefectureName", "cityName", "prefectureNameKana", "cityNameKana",
+       "createdAt", "updatedAt"
+FROM public."m_localGovernment"
+WHERE "cityName" = sqlc.arg(city_name)::text
+  AND (CASE WHEN sqlc.arg(prefecture_name)::text != '' THEN "prefectureName" = sqlc.arg(prefecture_name)::text ELSE TRUE END)
+ORDER BY id;
@@ -0,0 +1,9 @@
+-- name: GetOrganizationCategory :one
+SELECT id, "organizationCategoryNameJa", "organizationCategoryNameEn", "createdAt", "updatedAt"
+FROM public."m_organizationCategory"
+WHERE id = $1 LIMIT 1;
+
+-- name: ListOrganizationCategories :many
+SELECT id, "organizationCategoryNameJa", "organizationCategoryNameEn", "createdAt", "updatedAt"
+FROM public."m_organizationCategory"
+ORDER BY id;
@@ -0,0 +1,71 @@
+-- name: GlobalSearchSystems :many
+-- 横断検索（GET /api/v1/search）用。以下のGlobalSearch*も同じく、正規化した検索語で
+-- 部分一致（LIKE）または語類似度（<%）で絞り込み、スコア順に返す。
+-- 部分一致した行は類似度に関わらず上位になるようスコアを 1.0（備考のみの一致は 0.9）とする。
+WITH q AS (
+  SELECT public.normalize_search_text(sqlc.arg(query)::text) AS text,
+         '%' || replace(replace(replace(public.normalize_search_text(sqlc.arg(query)::text), '\', '\\'), '%', '\%'), '_', '\_') || '%' AS pattern
+)
+SELECT s.id, s."systemName", s."systemNameKana", s.remark,
+       GREATEST(
+         CASE WHEN s."searchText" LIKE q.pattern THEN 1.0 ELSE word_similarity(q.text, s."searchText") END,
+         CASE WHEN public.normalize_search_text(s.remark) LIKE q.pattern THEN 0.9 ELSE COALESCE(word_similarity(q.text, public.normalize_search_text(s.remark)), 0) END
+       )::real AS score
+FROM public.system s, q
+WHERE s."searchText" LIKE q.pattern
+   OR q.text <% s."searchText"
+   OR public.normalize_search_text(s.remark) LIKE q.pattern
+   OR q.text <% public.normalize_search_text(s.remark)
+ORDER BY score DESC, s."systemName"
+LIMIT sqlc.arg(max_results)::int;
+
+-- name: GlobalSearchProjects :many
+-- 法人番号は数字のみのため前方一致で照合する
+WITH q AS (
+  SELECT public.normalize_search_text(sqlc.arg(query)::text) AS text,
+         '%' || replace(replace(replace(public.normalize_search_text(sqlc.arg(query)::text), '\', '\\'), '%', '\%'), '_', '\_') || '%' AS pattern
+)
+SELECT p.id, p."projectName", p."vendorName", p."corporateNumber", p."localGovernmentId",
+       (CASE
+         WHEN p."corporateNumber" LIKE ltrim(q.pattern, '%') THEN 1.0
+         WHEN public.normalize_search_text(p."projectName" || ' ' || p."vendorName") LIKE q.pattern THEN 1.0
+         ELSE word_similarity(q.text, public.normalize_search_text(p."projectName" || ' ' || p."vendorName"))
+       END)::real AS score
+FROM public.project p, q
+WHERE p."corporateNumber" LIKE ltrim(q.pattern, '%')
+   OR public.normalize_search_text(p."projectName" || ' ' || p."vendorName") LIKE q.pattern
+   OR q.text <% public.normalize_search_text(p."projectName" || ' ' || p."vendorName")
+ORDER BY score DESC, p."projectName"
+LIMIT sqlc.arg(max_results)::int;
+
+-- name: GlobalSearchGroups :many
+WITH q AS (
+  SELECT public.normalize_search_text(sqlc.arg(query)::text) AS text,
+         '%' || replace(replace(replace(public.normalize_search_text(sqlc.arg(query)::text), '\', '\\'), '%', '\%'), '_', '\_') || '%' AS pattern
+)
+SELECT g.id, g."groupName",
+       (CASE
+         WHEN public.normalize_search_text(g."groupName") LIKE q.pattern THEN 1.0
+         ELSE word_similarity(q.text, public.normalize_search_text(g."groupName"))
+       END)::real AS score
+FROM public."gcasGroup" g, q
+WHERE public.normalize_search_text(g."groupName") LIKE q.pattern
+   OR q.text <% public.normalize_search_text(g."groupName")
+ORDER BY score DESC, g."groupName"
+LIMIT sqlc.arg(max_results)::int;
+
+-- name: GlobalSearchLocalGovernments :many
+WITH q AS (
+  SELECT public.normalize_search_text(sqlc.arg(query)::text) AS text,
+         '%' || replace(replace(replace(public.normalize_search_text(sqlc.arg(query)::text), '\', '\\'), '%', '\%'), '_', '\_') || '%' AS pattern
+)
+SELECT lg.id, lg."prefectureName", lg."cityName", lg."cityNameKana",
+       (CASE
+         WHEN public.normalize_search_text(lg."cityName" || ' ' || lg."cityNameKana") LIKE q.pattern THEN 1.0
+         ELSE word_similarity(q.text, public.normalize_search_text(lg."cityName" || ' ' || lg."cityNameKana"))
+       END)::real AS score
+FROM public."m_localGovernment" lg, q
+WHERE public.normalize_search_text(lg."cityName" || ' ' || lg."cityNameKana") LIKE q.pattern
+   OR q.text <% public.normalize_search_text(lg."cityName" || ' ' || lg."cityNameKana")
+ORDER BY score DESC, lg.id
+LIMIT sqlc.arg(max_results)::int;
@@ -1,55 +1,65 @@
 -- name: GetSystem :one
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE id = $1 LIMIT 1;
 
 -- name: GetSystems :many
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 ORDER BY "createdAt" DESC;
 
 -- name: GetSystemsByLocalGovernment :many
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE "localGovernmentId" = $1
 ORDER BY "createdAt" DESC;
 
 -- name: GetSystemByName :one
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE "systemName" = $1 LIMIT 1;
 
 -- name: GetSystemsByEmail :many
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE "mailAddress" = $1
 ORDER BY "createdAt" DESC;
 
 -- name: CreateSystem :one
-INSERT INTO public.system ("systemName", "localGovernmentId", "mailAddress", telephone, remark)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO public.system ("systemName", "localGovernmentId", "mailAddress", telephone, remark, "systemNameKana")
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-          "mailAddress", telephone, remark;
+          "mailAddress", telephone, remark, "systemNameKana", "searchText";
+
+-- name: CopySystems :copyfrom
+-- 一括取り込みの新規作成用（COPYで書き込む）
+INSERT INTO public.system ("systemName", "localGovernmentId", "mailAddress", telephone, remark, "systemNameKana")
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetSystemIDsByNames :many
+SELECT id, "systemName"
+FROM public.system
+WHERE "systemName" = ANY(sqlc.arg(system_names)::text[]);
 
 -- name: UpdateSystem :one
 UPDATE public.system
 SET "systemName" = $2, "localGovernmentId" = $3, "mailAddress" = $4, 
-    telephone = $5, remark = $6, "updatedAt" = now()
+    telephone = $5, remark = $6, "systemNameKana" = $7, "updatedAt" = now()
 WHERE id = $1
 RETURNING id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-          "mailAddress", telephone, remark;
+          "mailAddress", telephone, remark, "systemNameKana", "searchText";
 
 -- name: UpdateSystemContact :one
 UPDATE public.system
 SET "mailAddress" = $2, telephone = $3, "updatedAt" = now()
 WHERE id = $1
 RETURNING id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-          "mailAddress", telephone, remark;
+          "mailAddress", telephone, remark, "systemNameKana", "searchText";
 
 -- name: DeleteSystem :exec
 DELETE FROM public.system
@@ -57,10 +67,27 @@ WHERE id = $1;
 
 -- name: SearchSystems :many
 SELECT id, "systemName", "localGovernmentId", "createdAt", "updatedAt", 
-       "mailAddress", telephone, remark
+       "mailAddress", telephone, remark, "systemNameKana", "searchText"
 FROM public.system
 WHERE 
   (CASE WHEN $1::text != '' THEN "systemName" ILIKE '%' || $1 || '%' ELSE TRUE END)
   AND (CASE WHEN $2::text != '' THEN "mailAddress" = $2 ELSE TRUE END)
   AND (CASE WHEN $3::text != '' THEN "localGovernmentId" = $3 ELSE TRUE END)
-ORDER BY "createdAt" DESC; 
\ No newline at end of file
+ORDER BY "createdAt" DESC;
+
+-- name: FuzzySearchSystems :many
+-- 正規化した検索語で部分一致（LIKE）または語類似度（<%）で絞り込み、類似度順に返す
+WITH q AS (
+  SELECT public.normalize_search_text(sqlc.arg(query)::text) AS text
+)
+SELECT s.id, s."systemName", s."localGovernmentId", s."createdAt", s."updatedAt", 
+       s."mailAddress", s.telephone, s.remark, s."systemNameKana", s."searchText",
+       word_similarity(q.text, s."searchText")::real AS score
+FROM public.system s, q
+WHERE 
+  (s."searchText" LIKE '%' || replace(replace(replace(q.text, '\', '\\'), '%', '\%'), '_', '\_') || '%'
+   OR q.text <% s."searchText")
+  AND (CASE WHEN sqlc.arg(mail_address)::text != '' THEN s."mailAddress" = sqlc.arg(mail_address)::text ELSE TRUE END)
+  AND (CASE WHEN sqlc.arg(local_government_id)::text != '' THEN s."localGovernmentId" = sqlc.arg(local_government_id)::text ELSE TRUE END)
+ORDER BY score DESC, s."createdAt" DESC
+LIMIT sqlc.arg(max_results)::int;
@@ -0,0 +1,9 @@
+-- name: GetUserRole :one
+SELECT id, "roleNameJa", "roleNameEn", "createdAt", "updatedAt"
+FROM public."m_userRole"
+WHERE id = $1 LIMIT 1;
+
+-- name: ListUserRoles :many
+SELECT id, "roleNameJa", "roleNameEn", "createdAt", "updatedAt"
+FROM public."m_userRole"
+ORDER BY id;
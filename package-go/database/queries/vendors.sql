@@ -0,0 +1,48 @@
+-- name: ListVendors :many
+SELECT "corporateNumber", "vendorName", "vendorNameKana", "prefectureName", "cityName",
+       "streetNumber", "postCode", "closeDate", "ntaUpdatedAt"
+FROM public."vendorDirectory"
+WHERE (CASE WHEN sqlc.arg(corporate_number)::text != '' THEN "corporateNumber" = sqlc.arg(corporate_number)::text ELSE TRUE END)
+ORDER BY "corporateNumber";
+
+-- name: ListVendorProjects :many
+SELECT vp."corporateNumber", p.id, p."projectName", p."localGovernmentId"
+FROM public."vendorProject" vp
+JOIN public.project p ON p.id = vp."projectId"
+WHERE (CASE WHEN sqlc.arg(corporate_number)::text != '' THEN vp."corporateNumber" = sqlc.arg(corporate_number)::text ELSE TRUE END)
+ORDER BY vp."corporateNumber", p."projectName";
+
+-- name: ListVendorSystems :many
+-- ベンダーのプロジェクトに紐づくシステム（複数プロジェクトで重複するものは1件にまとめる）
+SELECT DISTINCT vp."corporateNumber", s.id, s."systemName"
+FROM public."vendorProject" vp
+JOIN public."projectSystemRelation" psr ON psr."projectId" = vp."projectId"
+JOIN public.system s ON s.id = psr."systemId"
+WHERE (CASE WHEN sqlc.arg(corporate_number)::text != '' THEN vp."corporateNumber" = sqlc.arg(corporate_number)::text ELSE TRUE END)
+ORDER BY vp."corporateNumber", s."systemName";
+
+-- name: ListReferencedCorporateNumbers :many
+-- プロジェクトまたはシステム基本情報に記載されている法人番号
+SELECT DISTINCT "corporateNumber"::text AS "corporateNumber"
+FROM public."vendorProject"
+ORDER BY "corporateNumber";
+
+-- name: UpsertVendor :exec
+-- 法人番号公表サイトの更新日が新しい場合のみ上書きする
+INSERT INTO public.vendor (
+  "corporateNumber", "vendorName", "vendorNameKana", "prefectureName", "cityName",
+  "streetNumber", "postCode", "closeDate", "ntaUpdatedAt"
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT ("corporateNumber") DO UPDATE SET
+  "vendorName" = EXCLUDED."vendorName",
+  "vendorNameKana" = EXCLUDED."vendorNameKana",
+  "prefectureName" = EXCLUDED."prefectureName",
+  "cityName" = EXCLUDED."cityName",
+  "streetNumber" = EXCLUDED."streetNumber",
+  "postCode" = EXCLUDED."postCode",
+  "closeDate" = EXCLUDED."closeDate",
+  "ntaUpdatedAt" = EXCLUDED."ntaUpdatedAt",
+  "updatedAt" = now()
+WHERE public.vendor."ntaUpdatedAt" <= EXCLUDED."ntaUpdatedAt";
@@ -0,0 +1,227 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/config"
+	"sample-micro-service-api/package-go/logging"
+)
+
+// replicaQueryPrefixes はレプリカに振り分ける参照系のクエリ名の接頭辞
+var replicaQueryPrefixes = []string{"Get", "Find", "List", "Search", "FuzzySearch", "GlobalSearch"}
+
+type primaryKey struct{}
+
+// WithPrimary はctxでの読み込みをプライマリに固定する（書き込み直後に自分の変更を読む場合など）
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary はctxでの読み込みがプライマリに固定されているかどうか
+func UsesPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// readOnlyQuery はnameのクエリがレプリカで実行できる参照系のクエリかどうか
+func readOnlyQuery(name string) bool {
+	for _, prefix := range replicaQueryPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// replica は読み込み専用のレプリカの接続プールと死活状態
+type replica struct {
+	name    string // ログ・メトリクス用の名前（replica-1, replica-2, ...）
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// replicaSet はレプリカをラウンドロビンで選び、定期的に死活を確認する
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	stop     context.CancelFunc
+	done     sync.WaitGroup
+}
+
+// openReplicas はcfg.ReplicaURLsの接続プールを作成し、死活監視を始める
+// 起動時に応答しないレプリカがあっても失敗にはせず、応答するまで振り分けない
+func openReplicas(cfg config.DatabaseConfig) (*replicaSet, error) {
+	set := &replicaSet{}
+	for i, url := range cfg.ReplicaURLs {
+		pool, err := newPool(context.Background(), cfg, url)
+		if err != nil {
+			set.close()
+			return nil, fmt.Errorf("failed to open replica %d: %w", i+1, err)
+		}
+		set.replicas = append(set.replicas, &replica{name: fmt.Sprintf("replica-%d", i+1), pool: pool})
+	}
+
+	logger := logging.Named(logging.GetLogger(), logging.LoggerDatabase)
+	set.check(context.Background(), cfg.PingTimeout, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	set.stop = cancel
+	set.done.Add(1)
+	go func() {
+		defer set.done.Done()
+		ticker := time.NewTicker(cfg.ReplicaCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				set.check(ctx, cfg.PingTimeout, logger)
+			}
+		}
+	}()
+	return set, nil
+}
+
+// check は各レプリカにPingし、応答の有無が変わったものを記録する
+func (s *replicaSet) check(ctx context.Context, timeout time.Duration, logger *zap.Logger) {
+	for _, r := range s.replicas {
+		err := ping(ctx, r.pool, timeout)
+		healthy := err == nil
+		if r.healthy.Swap(healthy) == healthy || ctx.Err() != nil {
+			continue
+		}
+		if healthy {
+			logger.Info("Replica is available", zap.String("replica", r.name))
+		} else {
+			logger.Warn("Replica is unavailable, reading from other replicas or the primary", zap.String("replica", r.name), zap.Error(err))
+		}
+	}
+}
+
+// pick は次に応答するレプリカをラウンドロビンで選ぶ。すべて応答しない場合はnil
+func (s *replicaSet) pick() *replica {
+	n := uint64(len(s.replicas))
+	start := s.next.Add(1)
+	for i := range n {
+		if r := s.replicas[(start+i)%n]; r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// markDown はクエリの失敗でレプリカを振り分け対象から外す（次の死活監視で応答すれば戻す）
+func (s *replicaSet) markDown(ctx context.Context, r *replica, err error) {
+	if r.healthy.Swap(false) {
+		logging.Named(logging.FromContext(ctx), logging.LoggerDatabase).Warn("Replica query failed, falling back to the primary",
+			zap.String("replica", r.name), zap.Error(err))
+	}
+}
+
+// pools はレプリカの名前ごとの接続プール（メトリクスの登録用）
+func (s *replicaSet) pools() map[string]*pgxpool.Pool {
+	pools := make(map[string]*pgxpool.Pool, len(s.replicas))
+	for _, r := range s.replicas {
+		pools[r.name] = r.pool
+	}
+	return pools
+}
+
+func (s *replicaSet) close() {
+	if s.stop != nil {
+		s.stop()
+		s.done.Wait()
+	}
+	for _, r := range s.replicas {
+		r.pool.Close()
+	}
+}
+
+// routingDBTX は参照系のクエリをレプリカに、それ以外をプライマリに振り分けるDBTX
+// クエリ名（"-- name: X"）が replicaQueryPrefixes で始まり、ctxがプライマリに固定されていない場合にレプリカを使う
+type routingDBTX struct {
+	primary  DBTX
+	replicas *replicaSet
+}
+
+// reader はクエリを実行する接続先を選ぶ。レプリカを選んだ場合はそのreplicaも返す
+func (r *routingDBTX) reader(ctx context.Context, query string) (DBTX, *replica) {
+	if UsesPrimary(ctx) || !readOnlyQuery(QueryName(query)) {
+		return r.primary, nil
+	}
+	rep := r.replicas.pick()
+	if rep == nil {
+		return r.primary, nil
+	}
+	return rep.pool, rep
+}
+
+func (r *routingDBTX) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	return r.primary.Exec(ctx, query, args...)
+}
+
+func (r *routingDBTX) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	target, rep := r.reader(ctx, query)
+	rows, err := target.Query(ctx, query, args...)
+	if err != nil && rep != nil && fallbackToPrimary(ctx, err) {
+		r.replicas.markDown(ctx, rep, err)
+		return r.primary.Query(ctx, query, args...)
+	}
+	return rows, err
+}
+
+func (r *routingDBTX) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	target, rep := r.reader(ctx, query)
+	row := target.QueryRow(ctx, query, args...)
+	if rep == nil {
+		return row
+	}
+	return &fallbackRow{ctx: ctx, row: row, retry: func(err error) pgx.Row {
+		r.replicas.markDown(ctx, rep, err)
+		return r.primary.QueryRow(ctx, query, args...)
+	}}
+}
+
+func (r *routingDBTX) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return r.primary.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// fallbackRow はレプリカでのScanが接続の問題で失敗した場合にプライマリで読み直すRow
+type fallbackRow struct {
+	ctx   context.Context
+	row   pgx.Row
+	retry func(err error) pgx.Row
+}
+
+func (r *fallbackRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if err != nil && fallbackToPrimary(r.ctx, err) {
+		return r.retry(err).Scan(dest...)
+	}
+	return err
+}
+
+// fallbackToPrimary はレプリカでの読み込みの失敗をプライマリで読み直すべきかどうか
+// 接続の失敗と、レプリカの適用との競合によるキャンセル（40001）が対象。SQLの誤り等はそのまま返す
+func fallbackToPrimary(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001"
+	}
+	return true
+}
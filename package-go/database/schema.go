@@ -0,0 +1,598 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/logging"
+)
+
+// schemaFilter は比較の対象外にするスキーマ（システムカタログ）の条件
+const schemaFilter = `n.nspname NOT IN ('pg_catalog', 'information_schema') AND n.nspname NOT LIKE 'pg_toast%' AND n.nspname NOT LIKE 'pg_temp%'`
+
+// ignoredTables はどちらのDBにもあり、比較しないテーブル（golang-migrateのバージョン管理）
+var ignoredTables = map[string]bool{"public.schema_migrations": true}
+
+// Schema はDBのテーブル・カラム・インデックス・外部キーの定義
+type Schema struct {
+	Tables map[string]*Table // キーは "schema.table"
+}
+
+// Table はテーブルの定義
+type Table struct {
+	Schema      string
+	Name        string
+	Columns     []Column // カラムの定義順
+	Indexes     map[string]Index
+	ForeignKeys map[string]ForeignKey
+}
+
+// Column はカラムの定義
+type Column struct {
+	Name      string
+	Type      string // format_typeの形式（character varying(255) など）
+	NotNull   bool
+	Default   string // 既定値の式（生成列の場合は生成式）
+	Identity  string // IDENTITY列の場合は "a"（ALWAYS）または "d"（BY DEFAULT）
+	Generated bool   // STOREDの生成列か
+}
+
+// Index はインデックスの定義
+type Index struct {
+	Name       string
+	Definition string // pg_get_indexdefのCREATE INDEX文
+	Constraint string // 主キー・一意制約のインデックスの場合は制約の定義（PRIMARY KEY (id) など）
+}
+
+// ForeignKey は外部キー制約の定義
+type ForeignKey struct {
+	Name       string
+	Definition string // pg_get_constraintdefの形式（FOREIGN KEY (...) REFERENCES ...）
+	Index      string // 参照先の主キー・一意のインデックス（"schema.index"）。定義の比較には使わない
+}
+
+// DriftKind はスキーマの差分の種類
+type DriftKind string
+
+const (
+	DriftMissing    DriftKind = "missing"    // マイグレーションにはあるがDBにない
+	DriftUnexpected DriftKind = "unexpected" // DBにはあるがマイグレーションにない
+	DriftChanged    DriftKind = "changed"    // 両方にあるが定義が異なる
+)
+
+// SchemaDrift はマイグレーションから作ったスキーマとDBのスキーマの1件の差分
+type SchemaDrift struct {
+	Kind     DriftKind
+	Object   string // table, column, index, foreign key
+	Table    string // "schema.table"
+	Name     string // カラム・インデックス・制約の名前（テーブルの場合は空）
+	Expected string // マイグレーションでの定義
+	Actual   string // DBでの定義
+
+	fix   string // 補正するSQL
+	order int    // 補正するSQLを並べる順序（依存する順）
+
+	dropsIndex string // 補正で削除するインデックス（"schema.index"、参照する外部キーを削除・追加し直すため）
+}
+
+// 補正するSQLの順序（外部キー・インデックスの削除 → テーブル・カラム → インデックス・外部キーの作成（定義の変更は削除してから作成） → データを失う削除）
+const (
+	orderDropForeignKey = iota
+	orderDropIndex
+	orderCreateTable
+	orderAlterColumn
+	orderCreateIndex
+	orderAddForeignKey
+	orderDestructive
+)
+
+// String は差分を1行で表す
+func (d SchemaDrift) String() string {
+	target := d.Table
+	if d.Name != "" {
+		target += "." + d.Name
+	}
+	switch d.Kind {
+	case DriftMissing:
+		return fmt.Sprintf("missing %s %s: %s", d.Object, target, d.Expected)
+	case DriftUnexpected:
+		return fmt.Sprintf("unexpected %s %s: %s", d.Object, target, d.Actual)
+	default:
+		return fmt.Sprintf("changed %s %s: expected %s, got %s", d.Object, target, d.Expected, d.Actual)
+	}
+}
+
+// SchemaVerification はDBのスキーマと、同じバージョンまでマイグレーションを適用したスキーマとの比較結果
+type SchemaVerification struct {
+	Version uint // 比較したバージョン（DBに適用済みのバージョン）
+	Latest  uint // 埋め込んだマイグレーションの最新バージョン
+	Dirty   bool
+	Drifts  []SchemaDrift
+}
+
+// VerifySchema はDBのスキーマを、適用済みのバージョンまでマイグレーションを適用した一時DBのスキーマと比較する
+// drizzleなどマイグレーション以外で変更されたテーブル・カラム・インデックス・外部キーを差分として返す
+// 一時DBを作成するためCREATEDB権限が必要
+func (c *Client) VerifySchema(ctx context.Context) (*SchemaVerification, error) {
+	migrator, err := NewMigrator(stdlib.OpenDBFromPool(c.Pool))
+	if err != nil {
+		return nil, err
+	}
+	status, err := migrator.Status()
+	migrator.Close()
+	if err != nil {
+		return nil, err
+	}
+	if status.Version == 0 {
+		return nil, fmt.Errorf("no migrations have been applied; run `up` first")
+	}
+
+	actual, err := InspectSchema(ctx, c.Pool)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := c.migratedSchema(ctx, status.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SchemaVerification{
+		Version: status.Version,
+		Latest:  status.Latest,
+		Dirty:   status.Dirty,
+		Drifts:  DiffSchema(expected, actual),
+	}, nil
+}
+
+// migratedSchema は一時DBを作成してversionまでマイグレーションを適用し、そのスキーマを返す（一時DBは削除する）
+func (c *Client) migratedSchema(ctx context.Context, version uint) (*Schema, error) {
+	connConfig := c.Pool.Config().ConnConfig.Copy()
+	scratch := fmt.Sprintf("schema_verify_%d", time.Now().UnixNano())
+	name := pgx.Identifier{scratch}.Sanitize()
+
+	if _, err := c.Pool.Exec(ctx, "CREATE DATABASE "+name+" TEMPLATE template0"); err != nil {
+		return nil, fmt.Errorf("failed to create scratch database %s: %w", scratch, err)
+	}
+	defer func() {
+		if _, err := c.Pool.Exec(context.Background(), "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+			logging.Named(logging.GetLogger(), logging.LoggerDatabase).Warn("Failed to drop scratch database",
+				zap.String("database", scratch), zap.Error(err))
+		}
+	}()
+
+	connConfig.Database = scratch
+	migrator, err := NewMigrator(stdlib.OpenDB(*connConfig))
+	if err != nil {
+		return nil, err
+	}
+	err = migrator.Goto(version)
+	migrator.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply migrations to scratch database: %w", err)
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, connConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to scratch database: %w", err)
+	}
+	defer conn.Close(context.Background())
+	return InspectSchema(ctx, conn)
+}
+
+// InspectSchema はDBのテーブル・カラム・インデックス・外部キーの定義を読み込む
+// 拡張機能が作成したテーブルとschema_migrationsは含めない
+func InspectSchema(ctx context.Context, db DBTX) (*Schema, error) {
+	schema := &Schema{Tables: map[string]*Table{}}
+
+	err := scanEach(ctx, db, `
+		SELECT n.nspname, c.relname
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p') AND `+schemaFilter+`
+		  AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = c.oid AND d.deptype = 'e')`,
+		func(rows pgx.Rows) error {
+			table := &Table{Indexes: map[string]Index{}, ForeignKeys: map[string]ForeignKey{}}
+			if err := rows.Scan(&table.Schema, &table.Name); err != nil {
+				return err
+			}
+			if key := table.Schema + "." + table.Name; !ignoredTables[key] {
+				schema.Tables[key] = table
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tables: %w", err)
+	}
+
+	err = scanEach(ctx, db, `
+		SELECT n.nspname, c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+		       COALESCE(pg_get_expr(ad.adbin, ad.adrelid), ''), a.attidentity::text, a.attgenerated = 's'
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+		WHERE c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped AND `+schemaFilter+`
+		ORDER BY n.nspname, c.relname, a.attnum`,
+		func(rows pgx.Rows) error {
+			var schemaName, tableName string
+			var column Column
+			if err := rows.Scan(&schemaName, &tableName, &column.Name, &column.Type, &column.NotNull,
+				&column.Default, &column.Identity, &column.Generated); err != nil {
+				return err
+			}
+			if table, ok := schema.Tables[schemaName+"."+tableName]; ok {
+				table.Columns = append(table.Columns, column)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	// 外部キーのconindidは参照先のインデックスを指すため、主キー・一意・排他制約のみ結合する
+	err = scanEach(ctx, db, `
+		SELECT n.nspname, t.relname, i.relname, pg_get_indexdef(i.oid), COALESCE(pg_get_constraintdef(con.oid), '')
+		FROM pg_index x
+		JOIN pg_class i ON i.oid = x.indexrelid
+		JOIN pg_class t ON t.oid = x.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		LEFT JOIN pg_constraint con ON con.conindid = x.indexrelid AND con.contype IN ('p', 'u', 'x')
+		WHERE t.relkind IN ('r', 'p') AND `+schemaFilter,
+		func(rows pgx.Rows) error {
+			var schemaName, tableName string
+			var index Index
+			if err := rows.Scan(&schemaName, &tableName, &index.Name, &index.Definition, &index.Constraint); err != nil {
+				return err
+			}
+			if table, ok := schema.Tables[schemaName+"."+tableName]; ok {
+				table.Indexes[index.Name] = index
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+
+	err = scanEach(ctx, db, `
+		SELECT n.nspname, t.relname, con.conname, pg_get_constraintdef(con.oid), COALESCE(rn.nspname || '.' || ri.relname, '')
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		LEFT JOIN pg_class ri ON ri.oid = con.conindid
+		LEFT JOIN pg_namespace rn ON rn.oid = ri.relnamespace
+		WHERE con.contype = 'f' AND `+schemaFilter,
+		func(rows pgx.Rows) error {
+			var schemaName, tableName string
+			var fk ForeignKey
+			if err := rows.Scan(&schemaName, &tableName, &fk.Name, &fk.Definition, &fk.Index); err != nil {
+				return err
+			}
+			if table, ok := schema.Tables[schemaName+"."+tableName]; ok {
+				table.ForeignKeys[fk.Name] = fk
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read foreign keys: %w", err)
+	}
+
+	return schema, nil
+}
+
+// scanEach はqueryの結果の行ごとにscanを呼ぶ
+func scanEach(ctx context.Context, db DBTX, query string, scan func(rows pgx.Rows) error) error {
+	rows, err := db.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DiffSchema はexpected（マイグレーション）に対するactual（DB）の差分を、テーブル名・名前の順に返す
+func DiffSchema(expected, actual *Schema) []SchemaDrift {
+	var drifts []SchemaDrift
+
+	for _, key := range sortedKeys(expected.Tables) {
+		want := expected.Tables[key]
+		got, ok := actual.Tables[key]
+		if !ok {
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftMissing, Object: "table", Table: key, Expected: "table",
+				fix: createTableSQL(want), order: orderCreateTable,
+			})
+			for _, name := range sortedKeys(want.Indexes) {
+				drifts = append(drifts, missingIndex(key, want, want.Indexes[name]))
+			}
+			for _, name := range sortedKeys(want.ForeignKeys) {
+				drifts = append(drifts, missingForeignKey(key, want, want.ForeignKeys[name]))
+			}
+			continue
+		}
+		drifts = append(drifts, diffTable(key, want, got)...)
+	}
+
+	for i := range drifts {
+		drifts[i] = withDependentForeignKeys(drifts[i], expected, actual)
+	}
+
+	for _, key := range sortedKeys(actual.Tables) {
+		if _, ok := expected.Tables[key]; !ok {
+			got := actual.Tables[key]
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftUnexpected, Object: "table", Table: key, Actual: "table",
+				fix: "-- DROP TABLE " + tableIdent(got) + ";", order: orderDestructive,
+			})
+		}
+	}
+
+	return drifts
+}
+
+// diffTable は両方にあるテーブルのカラム・インデックス・外部キーの差分を返す
+func diffTable(key string, want, got *Table) []SchemaDrift {
+	var drifts []SchemaDrift
+	table := tableIdent(want)
+
+	gotColumns := make(map[string]Column, len(got.Columns))
+	for _, column := range got.Columns {
+		gotColumns[column.Name] = column
+	}
+	wantColumns := make(map[string]bool, len(want.Columns))
+	for _, column := range want.Columns {
+		wantColumns[column.Name] = true
+		actual, ok := gotColumns[column.Name]
+		if !ok {
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftMissing, Object: "column", Table: key, Name: column.Name, Expected: columnDefinition(column),
+				fix: "ALTER TABLE " + table + " ADD COLUMN " + quoteIdent(column.Name) + " " + columnDefinition(column) + ";", order: orderAlterColumn,
+			})
+			continue
+		}
+		if columnDefinition(actual) != columnDefinition(column) {
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftChanged, Object: "column", Table: key, Name: column.Name,
+				Expected: columnDefinition(column), Actual: columnDefinition(actual),
+				fix: alterColumnSQL(table, column, actual), order: orderAlterColumn,
+			})
+		}
+	}
+	for _, column := range got.Columns {
+		if !wantColumns[column.Name] {
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftUnexpected, Object: "column", Table: key, Name: column.Name, Actual: columnDefinition(column),
+				fix: "-- ALTER TABLE " + table + " DROP COLUMN " + quoteIdent(column.Name) + ";", order: orderDestructive,
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(want.Indexes) {
+		index := want.Indexes[name]
+		actual, ok := got.Indexes[name]
+		switch {
+		case !ok:
+			drifts = append(drifts, missingIndex(key, want, index))
+		case actual != index:
+			drift := missingIndex(key, want, index)
+			drift.Kind, drift.Actual = DriftChanged, indexDefinition(actual)
+			drift.fix = dropIndexSQL(got, actual) + "\n" + drift.fix
+			drift.dropsIndex = indexKey(got, actual)
+			drifts = append(drifts, drift)
+		}
+	}
+	for _, name := range sortedKeys(got.Indexes) {
+		if _, ok := want.Indexes[name]; !ok {
+			index := got.Indexes[name]
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftUnexpected, Object: "index", Table: key, Name: name, Actual: indexDefinition(index),
+				fix: dropIndexSQL(got, index), order: orderDropIndex, dropsIndex: indexKey(got, index),
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(want.ForeignKeys) {
+		fk := want.ForeignKeys[name]
+		actual, ok := got.ForeignKeys[name]
+		switch {
+		case !ok:
+			drifts = append(drifts, missingForeignKey(key, want, fk))
+		case actual.Definition != fk.Definition:
+			// 参照先のインデックスの差分で先に削除されていることがあるためIF EXISTSにする
+			drift := missingForeignKey(key, want, fk)
+			drift.Kind, drift.Actual = DriftChanged, actual.Definition
+			drift.fix = "ALTER TABLE " + table + " DROP CONSTRAINT IF EXISTS " + quoteIdent(name) + ";\n" + drift.fix
+			drifts = append(drifts, drift)
+		}
+	}
+	for _, name := range sortedKeys(got.ForeignKeys) {
+		if _, ok := want.ForeignKeys[name]; !ok {
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftUnexpected, Object: "foreign key", Table: key, Name: name, Actual: got.ForeignKeys[name].Definition,
+				fix: "ALTER TABLE " + table + " DROP CONSTRAINT " + quoteIdent(name) + ";", order: orderDropForeignKey,
+			})
+		}
+	}
+
+	return drifts
+}
+
+// missingIndex はtableにindexを作成する差分を返す
+func missingIndex(key string, table *Table, index Index) SchemaDrift {
+	fix := index.Definition + ";"
+	if index.Constraint != "" {
+		fix = "ALTER TABLE " + tableIdent(table) + " ADD CONSTRAINT " + quoteIdent(index.Name) + " " + index.Constraint + ";"
+	}
+	return SchemaDrift{
+		Kind: DriftMissing, Object: "index", Table: key, Name: index.Name, Expected: indexDefinition(index),
+		fix: fix, order: orderCreateIndex,
+	}
+}
+
+// missingForeignKey はtableにfkを追加する差分を返す
+func missingForeignKey(key string, table *Table, fk ForeignKey) SchemaDrift {
+	return SchemaDrift{
+		Kind: DriftMissing, Object: "foreign key", Table: key, Name: fk.Name, Expected: fk.Definition,
+		fix: "ALTER TABLE " + tableIdent(table) + " ADD CONSTRAINT " + quoteIdent(fk.Name) + " " + fk.Definition + ";", order: orderAddForeignKey,
+	}
+}
+
+// withDependentForeignKeys はdriftが主キー・一意のインデックスを削除する場合、DBでそれを参照している外部キーを先に削除し、
+// 作り直した後に追加し直す（外部キーが参照しているインデックス・制約は削除できないため）
+// マイグレーションで定義が変わる・なくなる外部キーは、削除のみ行い自身の差分に任せる
+func withDependentForeignKeys(drift SchemaDrift, expected, actual *Schema) SchemaDrift {
+	if drift.dropsIndex == "" {
+		return drift
+	}
+
+	var drops, adds []string
+	for _, key := range sortedKeys(actual.Tables) {
+		table := actual.Tables[key]
+		for _, name := range sortedKeys(table.ForeignKeys) {
+			fk := table.ForeignKeys[name]
+			if fk.Index != drift.dropsIndex {
+				continue
+			}
+			drops = append(drops, "ALTER TABLE "+tableIdent(table)+" DROP CONSTRAINT IF EXISTS "+quoteIdent(name)+";")
+			if want, ok := expected.Tables[key]; ok {
+				if wantFK, ok := want.ForeignKeys[name]; ok && wantFK.Definition == fk.Definition {
+					adds = append(adds, "ALTER TABLE "+tableIdent(table)+" ADD CONSTRAINT "+quoteIdent(name)+" "+fk.Definition+";")
+				}
+			}
+		}
+	}
+	if len(drops) == 0 {
+		return drift
+	}
+
+	drift.fix = strings.Join(drops, "\n") + "\n" + drift.fix
+	if len(adds) > 0 {
+		drift.fix += "\n" + strings.Join(adds, "\n")
+	}
+	// 追加し直す外部キーが参照できるよう、不足しているインデックスを作成した後に実行する
+	if drift.order < orderCreateIndex {
+		drift.order = orderCreateIndex
+	}
+	return drift
+}
+
+// CorrectiveSQL はDBをマイグレーションのスキーマに合わせるSQLを返す
+// テーブル・カラムの削除はデータを失うためコメントアウトし、生成列・IDENTITYの変更は手で直すようコメントで示す
+func CorrectiveSQL(drifts []SchemaDrift) string {
+	sorted := make([]SchemaDrift, len(drifts))
+	copy(sorted, drifts)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].order < sorted[j].order })
+
+	var b strings.Builder
+	for _, drift := range sorted {
+		b.WriteString("-- " + drift.String() + "\n")
+		b.WriteString(drift.fix + "\n\n")
+	}
+	return b.String()
+}
+
+// createTableSQL はtableをカラムのみで作成するSQLを返す（インデックス・外部キーは別の差分で作成する）
+func createTableSQL(table *Table) string {
+	columns := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = "    " + quoteIdent(column.Name) + " " + columnDefinition(column)
+	}
+	return "CREATE TABLE " + tableIdent(table) + " (\n" + strings.Join(columns, ",\n") + "\n);"
+}
+
+// alterColumnSQL はactualのカラムをwantに合わせるSQLを返す
+func alterColumnSQL(table string, want, actual Column) string {
+	column := quoteIdent(want.Name)
+	if want.Generated != actual.Generated || want.Identity != actual.Identity ||
+		(want.Generated && want.Default != actual.Default) {
+		return "-- " + table + "." + column + ": generated or identity columns must be fixed by hand"
+	}
+
+	var statements []string
+	if want.Type != actual.Type {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", table, column, want.Type, column, want.Type))
+	}
+	if want.Default != actual.Default {
+		if want.Default == "" {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", table, column))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", table, column, want.Default))
+		}
+	}
+	if want.NotNull != actual.NotNull {
+		if want.NotNull {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", table, column))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", table, column))
+		}
+	}
+	return strings.Join(statements, "\n")
+}
+
+// dropIndexSQL はindexを削除するSQLを返す（制約のインデックスは制約ごと削除する）
+func dropIndexSQL(table *Table, index Index) string {
+	if index.Constraint != "" {
+		return "ALTER TABLE " + tableIdent(table) + " DROP CONSTRAINT " + quoteIdent(index.Name) + ";"
+	}
+	return "DROP INDEX " + pgx.Identifier{table.Schema, index.Name}.Sanitize() + ";"
+}
+
+// indexKey はindexを外部キーのIndexと比べる形式（"schema.index"）で返す
+func indexKey(table *Table, index Index) string {
+	return table.Schema + "." + index.Name
+}
+
+// columnDefinition はカラムの型と制約をCREATE TABLEの形式で返す
+func columnDefinition(column Column) string {
+	definition := column.Type
+	switch {
+	case column.Generated:
+		definition += " GENERATED ALWAYS AS (" + column.Default + ") STORED"
+	case column.Identity == "a":
+		definition += " GENERATED ALWAYS AS IDENTITY"
+	case column.Identity == "d":
+		definition += " GENERATED BY DEFAULT AS IDENTITY"
+	case column.Default != "":
+		definition += " DEFAULT " + column.Default
+	}
+	if column.NotNull {
+		definition += " NOT NULL"
+	}
+	return definition
+}
+
+// indexDefinition は差分の表示に使うインデックスの定義を返す
+func indexDefinition(index Index) string {
+	if index.Constraint != "" {
+		return index.Constraint
+	}
+	return index.Definition
+}
+
+func tableIdent(table *Table) string {
+	return pgx.Identifier{table.Schema, table.Name}.Sanitize()
+}
+
+func quoteIdent(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
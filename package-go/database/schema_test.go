@@ -0,0 +1,339 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+// testSchema はm_localGovernmentとそれを参照するsystemからなるスキーマを返す（呼び出しごとに新しく作る）
+func testSchema() *Schema {
+	localGovernment := &Table{
+		Schema: "public",
+		Name:   "m_localGovernment",
+		Columns: []Column{
+			{Name: "id", Type: "character varying(6)", NotNull: true},
+			{Name: "cityName", Type: "character varying(255)", NotNull: true},
+		},
+		Indexes: map[string]Index{
+			"m_localGovernment_pkey": {
+				Name:       "m_localGovernment_pkey",
+				Definition: `CREATE UNIQUE INDEX "m_localGovernment_pkey" ON public."m_localGovernment" USING btree (id)`,
+				Constraint: "PRIMARY KEY (id)",
+			},
+		},
+		ForeignKeys: map[string]ForeignKey{},
+	}
+	system := &Table{
+		Schema: "public",
+		Name:   "system",
+		Columns: []Column{
+			{Name: "id", Type: "uuid", NotNull: true, Default: "gen_random_uuid()"},
+			{Name: "systemName", Type: "character varying(255)", NotNull: true},
+			{Name: "localGovernmentId", Type: "character varying(6)"},
+			{Name: "searchText", Type: "text", NotNull: true, Default: `normalize_search_text(("systemName")::text)`, Generated: true},
+		},
+		Indexes: map[string]Index{
+			"system_pkey": {
+				Name:       "system_pkey",
+				Definition: `CREATE UNIQUE INDEX system_pkey ON public.system USING btree (id)`,
+				Constraint: "PRIMARY KEY (id)",
+			},
+		},
+		ForeignKeys: map[string]ForeignKey{
+			"system_localGovernmentId_fkey": {
+				Name:       "system_localGovernmentId_fkey",
+				Definition: `FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id)`,
+				Index:      "public.m_localGovernment_pkey",
+			},
+		},
+	}
+	return &Schema{Tables: map[string]*Table{
+		"public.m_localGovernment": localGovernment,
+		"public.system":            system,
+	}}
+}
+
+func TestDiffSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(expected, actual *Schema) // testSchemaから差分を作る
+		want   []string                       // SchemaDrift.String()
+		fixes  []string                       // 各差分の補正するSQL
+	}{
+		{
+			name:   "no drift",
+			mutate: func(expected, actual *Schema) {},
+		},
+		{
+			name: "missing table",
+			mutate: func(expected, actual *Schema) {
+				expected.Tables["public.vendor"] = &Table{
+					Schema: "public",
+					Name:   "vendor",
+					Columns: []Column{
+						{Name: "id", Type: "integer", NotNull: true, Identity: "a"},
+						{Name: "vendorName", Type: "character varying(255)", NotNull: true},
+					},
+					Indexes: map[string]Index{
+						"vendor_pkey": {Name: "vendor_pkey", Definition: "CREATE UNIQUE INDEX vendor_pkey ON public.vendor USING btree (id)", Constraint: "PRIMARY KEY (id)"},
+					},
+					ForeignKeys: map[string]ForeignKey{},
+				}
+			},
+			want: []string{
+				"missing table public.vendor: table",
+				"missing index public.vendor.vendor_pkey: PRIMARY KEY (id)",
+			},
+			fixes: []string{
+				"CREATE TABLE \"public\".\"vendor\" (\n    \"id\" integer GENERATED ALWAYS AS IDENTITY NOT NULL,\n    \"vendorName\" character varying(255) NOT NULL\n);",
+				`ALTER TABLE "public"."vendor" ADD CONSTRAINT "vendor_pkey" PRIMARY KEY (id);`,
+			},
+		},
+		{
+			name: "unexpected table",
+			mutate: func(expected, actual *Schema) {
+				actual.Tables["public.tmp"] = &Table{Schema: "public", Name: "tmp", Indexes: map[string]Index{}, ForeignKeys: map[string]ForeignKey{}}
+			},
+			want:  []string{"unexpected table public.tmp: table"},
+			fixes: []string{`-- DROP TABLE "public"."tmp";`},
+		},
+		{
+			name: "missing column",
+			mutate: func(expected, actual *Schema) {
+				table := expected.Tables["public.system"]
+				table.Columns = append(table.Columns, Column{Name: "remark", Type: "text"})
+			},
+			want:  []string{"missing column public.system.remark: text"},
+			fixes: []string{`ALTER TABLE "public"."system" ADD COLUMN "remark" text;`},
+		},
+		{
+			name: "unexpected column",
+			mutate: func(expected, actual *Schema) {
+				table := actual.Tables["public.system"]
+				table.Columns = append(table.Columns, Column{Name: "note", Type: "text", Default: "''::text", NotNull: true})
+			},
+			want:  []string{"unexpected column public.system.note: text DEFAULT ''::text NOT NULL"},
+			fixes: []string{`-- ALTER TABLE "public"."system" DROP COLUMN "note";`},
+		},
+		{
+			name: "changed column type, default and nullability",
+			mutate: func(expected, actual *Schema) {
+				actual.Tables["public.system"].Columns[1] = Column{Name: "systemName", Type: "text", Default: "''::text"}
+			},
+			want: []string{"changed column public.system.systemName: expected character varying(255) NOT NULL, got text DEFAULT ''::text"},
+			fixes: []string{
+				`ALTER TABLE "public"."system" ALTER COLUMN "systemName" TYPE character varying(255) USING "systemName"::character varying(255);` + "\n" +
+					`ALTER TABLE "public"."system" ALTER COLUMN "systemName" DROP DEFAULT;` + "\n" +
+					`ALTER TABLE "public"."system" ALTER COLUMN "systemName" SET NOT NULL;`,
+			},
+		},
+		{
+			name: "changed generated column",
+			mutate: func(expected, actual *Schema) {
+				actual.Tables["public.system"].Columns[3].Default = `lower(("systemName")::text)`
+			},
+			want:  []string{`changed column public.system.searchText: expected text GENERATED ALWAYS AS (normalize_search_text(("systemName")::text)) STORED NOT NULL, got text GENERATED ALWAYS AS (lower(("systemName")::text)) STORED NOT NULL`},
+			fixes: []string{`-- "public"."system"."searchText": generated or identity columns must be fixed by hand`},
+		},
+		{
+			name: "missing index",
+			mutate: func(expected, actual *Schema) {
+				expected.Tables["public.system"].Indexes["system_systemName_idx"] = Index{
+					Name:       "system_systemName_idx",
+					Definition: `CREATE INDEX "system_systemName_idx" ON public.system USING btree ("systemName")`,
+				}
+			},
+			want:  []string{`missing index public.system.system_systemName_idx: CREATE INDEX "system_systemName_idx" ON public.system USING btree ("systemName")`},
+			fixes: []string{`CREATE INDEX "system_systemName_idx" ON public.system USING btree ("systemName");`},
+		},
+		{
+			name: "unexpected index",
+			mutate: func(expected, actual *Schema) {
+				actual.Tables["public.system"].Indexes["system_extra_idx"] = Index{
+					Name:       "system_extra_idx",
+					Definition: `CREATE INDEX system_extra_idx ON public.system USING btree ("localGovernmentId")`,
+				}
+			},
+			want:  []string{`unexpected index public.system.system_extra_idx: CREATE INDEX system_extra_idx ON public.system USING btree ("localGovernmentId")`},
+			fixes: []string{`DROP INDEX "public"."system_extra_idx";`},
+		},
+		{
+			name: "changed index",
+			mutate: func(expected, actual *Schema) {
+				expected.Tables["public.system"].Indexes["system_systemName_idx"] = Index{
+					Name:       "system_systemName_idx",
+					Definition: `CREATE INDEX "system_systemName_idx" ON public.system USING btree ("systemName")`,
+				}
+				actual.Tables["public.system"].Indexes["system_systemName_idx"] = Index{
+					Name:       "system_systemName_idx",
+					Definition: `CREATE INDEX "system_systemName_idx" ON public.system USING hash ("systemName")`,
+				}
+			},
+			want: []string{`changed index public.system.system_systemName_idx: expected CREATE INDEX "system_systemName_idx" ON public.system USING btree ("systemName"), got CREATE INDEX "system_systemName_idx" ON public.system USING hash ("systemName")`},
+			fixes: []string{
+				`DROP INDEX "public"."system_systemName_idx";` + "\n" +
+					`CREATE INDEX "system_systemName_idx" ON public.system USING btree ("systemName");`,
+			},
+		},
+		{
+			name: "missing foreign key",
+			mutate: func(expected, actual *Schema) {
+				delete(actual.Tables["public.system"].ForeignKeys, "system_localGovernmentId_fkey")
+			},
+			want:  []string{`missing foreign key public.system.system_localGovernmentId_fkey: FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id)`},
+			fixes: []string{`ALTER TABLE "public"."system" ADD CONSTRAINT "system_localGovernmentId_fkey" FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id);`},
+		},
+		{
+			name: "unexpected foreign key",
+			mutate: func(expected, actual *Schema) {
+				delete(expected.Tables["public.system"].ForeignKeys, "system_localGovernmentId_fkey")
+			},
+			want:  []string{`unexpected foreign key public.system.system_localGovernmentId_fkey: FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id)`},
+			fixes: []string{`ALTER TABLE "public"."system" DROP CONSTRAINT "system_localGovernmentId_fkey";`},
+		},
+		{
+			name: "changed foreign key",
+			mutate: func(expected, actual *Schema) {
+				fk := actual.Tables["public.system"].ForeignKeys["system_localGovernmentId_fkey"]
+				fk.Definition += " ON DELETE CASCADE"
+				actual.Tables["public.system"].ForeignKeys["system_localGovernmentId_fkey"] = fk
+			},
+			want: []string{`changed foreign key public.system.system_localGovernmentId_fkey: expected FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id), got FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id) ON DELETE CASCADE`},
+			fixes: []string{
+				`ALTER TABLE "public"."system" DROP CONSTRAINT IF EXISTS "system_localGovernmentId_fkey";` + "\n" +
+					`ALTER TABLE "public"."system" ADD CONSTRAINT "system_localGovernmentId_fkey" FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id);`,
+			},
+		},
+		{
+			name: "foreign key referencing a different index is not a drift",
+			mutate: func(expected, actual *Schema) {
+				fk := actual.Tables["public.system"].ForeignKeys["system_localGovernmentId_fkey"]
+				fk.Index = "public.m_localGovernment_id_key"
+				actual.Tables["public.system"].ForeignKeys["system_localGovernmentId_fkey"] = fk
+			},
+		},
+		{
+			name: "changed primary key referenced by an unchanged foreign key",
+			mutate: func(expected, actual *Schema) {
+				index := actual.Tables["public.m_localGovernment"].Indexes["m_localGovernment_pkey"]
+				index.Definition = `CREATE UNIQUE INDEX "m_localGovernment_pkey" ON public."m_localGovernment" USING btree (id, "cityName")`
+				index.Constraint = `PRIMARY KEY (id, "cityName")`
+				actual.Tables["public.m_localGovernment"].Indexes["m_localGovernment_pkey"] = index
+			},
+			want: []string{`changed index public.m_localGovernment.m_localGovernment_pkey: expected PRIMARY KEY (id), got PRIMARY KEY (id, "cityName")`},
+			fixes: []string{
+				`ALTER TABLE "public"."system" DROP CONSTRAINT IF EXISTS "system_localGovernmentId_fkey";` + "\n" +
+					`ALTER TABLE "public"."m_localGovernment" DROP CONSTRAINT "m_localGovernment_pkey";` + "\n" +
+					`ALTER TABLE "public"."m_localGovernment" ADD CONSTRAINT "m_localGovernment_pkey" PRIMARY KEY (id);` + "\n" +
+					`ALTER TABLE "public"."system" ADD CONSTRAINT "system_localGovernmentId_fkey" FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id);`,
+			},
+		},
+		{
+			name: "unexpected unique constraint referenced by a changed foreign key",
+			mutate: func(expected, actual *Schema) {
+				actual.Tables["public.m_localGovernment"].Indexes["m_localGovernment_cityName_key"] = Index{
+					Name:       "m_localGovernment_cityName_key",
+					Definition: `CREATE UNIQUE INDEX "m_localGovernment_cityName_key" ON public."m_localGovernment" USING btree ("cityName")`,
+					Constraint: `UNIQUE ("cityName")`,
+				}
+				actual.Tables["public.system"].ForeignKeys["system_localGovernmentId_fkey"] = ForeignKey{
+					Name:       "system_localGovernmentId_fkey",
+					Definition: `FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"("cityName")`,
+					Index:      "public.m_localGovernment_cityName_key",
+				}
+			},
+			want: []string{
+				`unexpected index public.m_localGovernment.m_localGovernment_cityName_key: UNIQUE ("cityName")`,
+				`changed foreign key public.system.system_localGovernmentId_fkey: expected FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id), got FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"("cityName")`,
+			},
+			fixes: []string{
+				// 定義が変わる外部キーは削除のみ行い、追加し直すのは外部キー自身の差分
+				`ALTER TABLE "public"."system" DROP CONSTRAINT IF EXISTS "system_localGovernmentId_fkey";` + "\n" +
+					`ALTER TABLE "public"."m_localGovernment" DROP CONSTRAINT "m_localGovernment_cityName_key";`,
+				`ALTER TABLE "public"."system" DROP CONSTRAINT IF EXISTS "system_localGovernmentId_fkey";` + "\n" +
+					`ALTER TABLE "public"."system" ADD CONSTRAINT "system_localGovernmentId_fkey" FOREIGN KEY ("localGovernmentId") REFERENCES "m_localGovernment"(id);`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, actual := testSchema(), testSchema()
+			tt.mutate(expected, actual)
+
+			drifts := DiffSchema(expected, actual)
+			if len(drifts) != len(tt.want) {
+				t.Fatalf("got %d drifts, want %d: %v", len(drifts), len(tt.want), drifts)
+			}
+			for i, drift := range drifts {
+				if got := drift.String(); got != tt.want[i] {
+					t.Errorf("drift[%d] = %s\nwant %s", i, got, tt.want[i])
+				}
+				if drift.fix != tt.fixes[i] {
+					t.Errorf("drift[%d] fix =\n%s\nwant\n%s", i, drift.fix, tt.fixes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCorrectiveSQL(t *testing.T) {
+	expected, actual := testSchema(), testSchema()
+
+	// マイグレーションにのみあるテーブル・カラム・インデックス・外部キー
+	expected.Tables["public.vendor"] = &Table{
+		Schema:      "public",
+		Name:        "vendor",
+		Columns:     []Column{{Name: "id", Type: "integer", NotNull: true}},
+		Indexes:     map[string]Index{},
+		ForeignKeys: map[string]ForeignKey{},
+	}
+	system := expected.Tables["public.system"]
+	system.Columns = append(system.Columns, Column{Name: "vendorId", Type: "integer"})
+	system.Indexes["system_vendorId_idx"] = Index{Name: "system_vendorId_idx", Definition: `CREATE INDEX "system_vendorId_idx" ON public.system USING btree ("vendorId")`}
+	system.ForeignKeys["system_vendorId_fkey"] = ForeignKey{Name: "system_vendorId_fkey", Definition: `FOREIGN KEY ("vendorId") REFERENCES vendor(id)`}
+
+	// DBにのみあるテーブル・カラム・インデックス・外部キー
+	actual.Tables["public.tmp"] = &Table{Schema: "public", Name: "tmp", Indexes: map[string]Index{}, ForeignKeys: map[string]ForeignKey{}}
+	system = actual.Tables["public.system"]
+	system.Columns = append(system.Columns, Column{Name: "note", Type: "text"})
+	system.Indexes["system_note_idx"] = Index{Name: "system_note_idx", Definition: `CREATE INDEX system_note_idx ON public.system USING btree (note)`}
+	system.ForeignKeys["system_tmp_fkey"] = ForeignKey{Name: "system_tmp_fkey", Definition: `FOREIGN KEY (note) REFERENCES tmp(id)`}
+
+	sql := CorrectiveSQL(DiffSchema(expected, actual))
+
+	// 外部キー・インデックスの削除 → テーブル・カラム → インデックス・外部キーの作成 → データを失う削除
+	steps := []string{
+		`ALTER TABLE "public"."system" DROP CONSTRAINT "system_tmp_fkey";`,
+		`DROP INDEX "public"."system_note_idx";`,
+		`CREATE TABLE "public"."vendor"`,
+		`ALTER TABLE "public"."system" ADD COLUMN "vendorId" integer;`,
+		`CREATE INDEX "system_vendorId_idx" ON public.system USING btree ("vendorId");`,
+		`ALTER TABLE "public"."system" ADD CONSTRAINT "system_vendorId_fkey" FOREIGN KEY ("vendorId") REFERENCES vendor(id);`,
+		`-- ALTER TABLE "public"."system" DROP COLUMN "note";`,
+		`-- DROP TABLE "public"."tmp";`,
+	}
+	last := -1
+	for _, step := range steps {
+		i := strings.Index(sql, step)
+		if i < 0 {
+			t.Fatalf("corrective SQL does not contain %q:\n%s", step, sql)
+		}
+		if i < last {
+			t.Errorf("%q is out of order:\n%s", step, sql)
+		}
+		last = i
+	}
+
+	// データを失う削除は実行されないようコメントアウトする
+	for _, line := range strings.Split(sql, "\n") {
+		if (strings.Contains(line, "DROP TABLE") || strings.Contains(line, "DROP COLUMN")) && !strings.HasPrefix(line, "-- ") {
+			t.Errorf("destructive statement is not commented out: %s", line)
+		}
+	}
+
+	// 各差分の前に差分の説明をコメントで付ける
+	if !strings.Contains(sql, "-- unexpected column public.system.note: text\n") {
+		t.Errorf("corrective SQL does not describe the drift:\n%s", sql)
+	}
+}
@@ -2,73 +2,80 @@ package seed
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 
 	"sample-micro-service-api/package-go/database/internal/db"
 )
 
 // SeedSystems inserts test data for systems table
-func SeedSystems(database *sql.DB) error {
+func SeedSystems(database db.DBTX) error {
 	queries := db.New(database)
 	ctx := context.Background()
 
 	systems := []db.CreateSystemParams{
 		{
 			SystemName:        "住民基本台帳システム",
-			LocalGovernmentId: sql.NullString{String: "", Valid: false}, // null
+			SystemNameKana:    stringPtr("ジュウミンキホンダイチョウシステム"),
+			LocalGovernmentId: nil, // null
 			MailAddress:       "juki-admin@chiyoda.tokyo.jp",
-			Telephone:         sql.NullString{String: "03-1234-5678", Valid: true},
-			Remark:           sql.NullString{String: "住民基本台帳の管理を行うシステム", Valid: true},
+			Telephone:         stringPtr("03-1234-5678"),
+			Remark:            stringPtr("住民基本台帳の管理を行うシステム"),
 		},
 		{
 			SystemName:        "税務管理システム",
-			LocalGovernmentId: sql.NullString{String: "", Valid: false}, // null
+			SystemNameKana:    stringPtr("ゼイムカンリシステム"),
+			LocalGovernmentId: nil, // null
 			MailAddress:       "zeimu-admin@chiyoda.tokyo.jp",
-			Telephone:         sql.NullString{String: "03-1234-5679", Valid: true},
-			Remark:           sql.NullString{String: "税務関連業務の管理システム", Valid: true},
+			Telephone:         stringPtr("03-1234-5679"),
+			Remark:            stringPtr("税務関連業務の管理システム"),
 		},
 		{
 			SystemName:        "健康管理システム",
-			LocalGovernmentId: sql.NullString{String: "", Valid: false}, // null
+			SystemNameKana:    stringPtr("ケンコウカンリシステム"),
+			LocalGovernmentId: nil, // null
 			MailAddress:       "kenkou-admin@yokohama.lg.jp",
-			Telephone:         sql.NullString{String: "045-1234-5678", Valid: true},
-			Remark:           sql.NullString{String: "市民の健康管理を支援するシステム", Valid: true},
+			Telephone:         stringPtr("045-1234-5678"),
+			Remark:            stringPtr("市民の健康管理を支援するシステム"),
 		},
 		{
 			SystemName:        "介護保険システム",
-			LocalGovernmentId: sql.NullString{String: "", Valid: false}, // null
+			SystemNameKana:    stringPtr("カイゴホケンシステム"),
+			LocalGovernmentId: nil, // null
 			MailAddress:       "kaigo-admin@yokohama.lg.jp",
-			Telephone:         sql.NullString{String: "045-1234-5679", Valid: true},
-			Remark:           sql.NullString{String: "介護保険業務の管理システム", Valid: true},
+			Telephone:         stringPtr("045-1234-5679"),
+			Remark:            stringPtr("介護保険業務の管理システム"),
 		},
 		{
 			SystemName:        "教育情報システム",
-			LocalGovernmentId: sql.NullString{String: "", Valid: false}, // null
+			SystemNameKana:    stringPtr("キョウイクジョウホウシステム"),
+			LocalGovernmentId: nil, // null
 			MailAddress:       "kyoiku-admin@nagoya.lg.jp",
-			Telephone:         sql.NullString{String: "052-1234-5678", Valid: true},
-			Remark:           sql.NullString{String: "教育関連情報の管理システム", Valid: true},
+			Telephone:         stringPtr("052-1234-5678"),
+			Remark:            stringPtr("教育関連情報の管理システム"),
 		},
 		{
 			SystemName:        "共通基盤システム",
-			LocalGovernmentId: sql.NullString{String: "", Valid: false}, // null
+			SystemNameKana:    stringPtr("キョウツウキバンシステム"),
+			LocalGovernmentId: nil, // null
 			MailAddress:       "platform-admin@gov-cloud.go.jp",
-			Telephone:         sql.NullString{String: "03-0000-0000", Valid: true},
-			Remark:           sql.NullString{String: "自治体共通で使用する基盤システム", Valid: true},
+			Telephone:         stringPtr("03-0000-0000"),
+			Remark:            stringPtr("自治体共通で使用する基盤システム"),
 		},
 		{
 			SystemName:        "災害対応システム",
-			LocalGovernmentId: sql.NullString{String: "", Valid: false}, // null
+			SystemNameKana:    stringPtr("サイガイタイオウシステム"),
+			LocalGovernmentId: nil, // null
 			MailAddress:       "saigai-admin@osaka.lg.jp",
-			Telephone:         sql.NullString{String: "06-1234-5678", Valid: true},
-			Remark:           sql.NullString{String: "災害時の対応管理システム", Valid: true},
+			Telephone:         stringPtr("06-1234-5678"),
+			Remark:            stringPtr("災害時の対応管理システム"),
 		},
 		{
 			SystemName:        "図書館管理システム",
-			LocalGovernmentId: sql.NullString{String: "", Valid: false}, // null
+			SystemNameKana:    stringPtr("トショカンカンリシステム"),
+			LocalGovernmentId: nil, // null
 			MailAddress:       "library-admin@chiyoda.tokyo.jp",
-			Telephone:         sql.NullString{String: "", Valid: false},
-			Remark:           sql.NullString{String: "図書館の蔵書・貸出管理システム", Valid: true},
+			Telephone:         nil,
+			Remark:            stringPtr("図書館の蔵書・貸出管理システム"),
 		},
 	}
 
@@ -84,3 +91,7 @@ func SeedSystems(database *sql.DB) error {
 	fmt.Printf("Successfully seeded %d systems\n", len(systems))
 	return nil
 }
+
+func stringPtr(s string) *string {
+	return &s
+}
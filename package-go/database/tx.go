@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+
+	"sample-micro-service-api/package-go/database/internal/db"
+	"sample-micro-service-api/package-go/logging"
+)
+
+// maxTxAttempts はシリアライゼーションの失敗・デッドロックの際にトランザクションを実行する最大回数（初回を含む）
+const maxTxAttempts = 3
+
+// txRetryBackoff は再試行までの待ち時間の単位（attempt倍し、ばらつかせる）
+const txRetryBackoff = 20 * time.Millisecond
+
+type txKey struct{}
+
+// txState はctxに載せる実行中のトランザクション（1つのゴルーチンからのみ使う）
+type txState struct {
+	tx      pgx.Tx
+	queries *Queries
+}
+
+// InTx はfnを1つのトランザクションで実行する
+// fnがエラーを返すかpanicした場合はロールバックし、シリアライゼーションの失敗（40001）・デッドロック（40P01）の場合は
+// トランザクション全体を最大maxTxAttempts回まで実行し直す。fnは複数回呼ばれることがあるため、DB以外の副作用を持たせないこと。
+//
+// fnに渡すctxをInTx・QueriesForに渡すと同じトランザクションが使われる。
+// すでにトランザクションの中の場合はセーブポイントで入れ子にし（optsは無視する）、fnの失敗時はセーブポイントまでロールバックする。
+// 読み込み専用でないトランザクションをコミットした場合、読み込みキャッシュをすべて破棄する。
+func (c *Client) InTx(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context, q *Queries) error) error {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return c.runTx(ctx, state.tx.Begin, fn)
+	}
+
+	begin := func(ctx context.Context) (pgx.Tx, error) {
+		return c.Pool.BeginTx(ctx, opts)
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = c.runTx(ctx, begin, fn)
+		if err == nil || !retryableTxError(err) || attempt == maxTxAttempts {
+			break
+		}
+
+		wait := time.Duration(attempt)*txRetryBackoff + rand.N(txRetryBackoff)
+		logging.Named(logging.FromContext(ctx), logging.LoggerDatabase).Warn("Retrying transaction",
+			zap.Int("attempt", attempt),
+			zap.Duration("retryIn", wait),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.cache != nil && opts.AccessMode != pgx.ReadOnly {
+		c.cache.Invalidate("")
+	}
+	return nil
+}
+
+// QueriesFor はctxがInTxの中であればそのトランザクションのクエリを、そうでなければQueriesを返す
+func (c *Client) QueriesFor(ctx context.Context) Querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.queries
+	}
+	return c.Queries
+}
+
+// runTx はbeginで開始したトランザクション（入れ子の場合はセーブポイント）でfnを1回実行する
+func (c *Client) runTx(ctx context.Context, begin func(ctx context.Context) (pgx.Tx, error), fn func(ctx context.Context, q *Queries) error) error {
+	tx, err := begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	state := &txState{tx: tx, queries: db.New(WithQueryHooks(tx, c.hooks...))}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, state), state.queries); err != nil {
+		if rollbackErr := tx.Rollback(context.WithoutCancel(ctx)); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// retryableTxError はトランザクションを実行し直せば成功する可能性があるエラー（シリアライゼーションの失敗・デッドロック）かどうか
+func retryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
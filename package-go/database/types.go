@@ -7,17 +7,21 @@ import (
 
 // Re-export main entity types
 type (
-	GcasUser              = internaldb.GcasUser
-	GcasGroup             = internaldb.GcasGroup
-	GcasGroupUserRelation = internaldb.GcasGroupUserRelation
+	GcasUser                = internaldb.GcasUser
+	GcasGroup               = internaldb.GcasGroup
+	GcasGroupUserRelation   = internaldb.GcasGroupUserRelation
 	GcasGroupSystemRelation = internaldb.GcasGroupSystemRelation
-	Project               = internaldb.Project
-	ProjectCost           = internaldb.ProjectCost
-	ProjectSystemRelation = internaldb.ProjectSystemRelation
-	System                = internaldb.System
-	SystemBasicInformation = internaldb.SystemBasicInformation
-	Queries               = internaldb.Queries
-	DBTX                  = internaldb.DBTX
+	Project                 = internaldb.Project
+	ProjectCost             = internaldb.ProjectCost
+	ProjectSystemRelation   = internaldb.ProjectSystemRelation
+	System                  = internaldb.System
+	SystemBasicInformation  = internaldb.SystemBasicInformation
+	Vendor                  = internaldb.Vendor
+	VendorDirectory         = internaldb.VendorDirectory
+	VendorProject           = internaldb.VendorProject
+	Queries                 = internaldb.Queries
+	Querier                 = internaldb.Querier
+	DBTX                    = internaldb.DBTX
 )
 
 // Re-export master table types
@@ -27,16 +31,43 @@ type (
 	MUserRole             = internaldb.MUserRole
 )
 
-
 // Re-export parameter types for System
 type (
 	CreateSystemParams        = internaldb.CreateSystemParams
+	CopySystemsParams         = internaldb.CopySystemsParams
+	GetSystemIDsByNamesRow    = internaldb.GetSystemIDsByNamesRow
 	UpdateSystemParams        = internaldb.UpdateSystemParams
 	UpdateSystemContactParams = internaldb.UpdateSystemContactParams
 	SearchSystemsParams       = internaldb.SearchSystemsParams
+	FuzzySearchSystemsParams  = internaldb.FuzzySearchSystemsParams
+	FuzzySearchSystemsRow     = internaldb.FuzzySearchSystemsRow
+)
+
+// Re-export parameter and row types for global search
+type (
+	GlobalSearchSystemsParams          = internaldb.GlobalSearchSystemsParams
+	GlobalSearchSystemsRow             = internaldb.GlobalSearchSystemsRow
+	GlobalSearchProjectsParams         = internaldb.GlobalSearchProjectsParams
+	GlobalSearchProjectsRow            = internaldb.GlobalSearchProjectsRow
+	GlobalSearchGroupsParams           = internaldb.GlobalSearchGroupsParams
+	GlobalSearchGroupsRow              = internaldb.GlobalSearchGroupsRow
+	GlobalSearchLocalGovernmentsParams = internaldb.GlobalSearchLocalGovernmentsParams
+	GlobalSearchLocalGovernmentsRow    = internaldb.GlobalSearchLocalGovernmentsRow
+)
+
+// Re-export parameter and row types for Vendor
+type (
+	ListVendorProjectsRow = internaldb.ListVendorProjectsRow
+	ListVendorSystemsRow  = internaldb.ListVendorSystemsRow
+	UpsertVendorParams    = internaldb.UpsertVendorParams
+)
+
+// Re-export parameter types for LocalGovernment
+type (
+	FindLocalGovernmentsByNameParams = internaldb.FindLocalGovernmentsByNameParams
 )
 
 // Re-export constructor
 func New(db DBTX) *Queries {
 	return internaldb.New(db)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,111 @@
+// Package health はレディネスチェックの登録と実行を行う
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ステータス
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// Checker は依存先1件分のチェック
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc は関数をCheckerとして使うためのアダプター
+type CheckerFunc func(ctx context.Context) error
+
+// Check はf(ctx)を呼び出す
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result は1件分のチェック結果
+type Result struct {
+	Name    string
+	Status  string
+	Latency time.Duration
+	Error   string
+}
+
+// Report はすべてのチェック結果
+type Report struct {
+	Status string // いずれかが失敗していればStatusFail
+	Checks []Result
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// Registry はチェックを登録順に保持し、まとめて実行する
+type Registry struct {
+	timeout  time.Duration
+	mu       sync.RWMutex
+	checkers []namedChecker
+}
+
+// NewRegistry は各チェックをtimeoutで打ち切るRegistryを作成
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register はチェックを追加する
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, namedChecker{name: name, checker: checker})
+}
+
+// Run はすべてのチェックを並行に実行し、登録順に結果を返す
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checkers := append([]namedChecker(nil), r.checkers...)
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make([]Result, len(checkers))}
+
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c namedChecker) {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+			break
+		}
+	}
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, c namedChecker) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.checker.Check(ctx)
+	result := Result{
+		Name:    c.name,
+		Status:  StatusOK,
+		Latency: time.Since(start),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
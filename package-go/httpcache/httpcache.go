@@ -0,0 +1,62 @@
+// Package httpcache はコレクションのGETに条件付きリクエスト（ETag・If-None-Match）を適用する
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CacheControl はコレクションのレスポンスに付けるCache-Control
+// 個人情報を含むため共有キャッシュには保存させず、ブラウザには毎回再検証させる
+const CacheControl = "private, no-cache"
+
+// ETag はレスポンスのボディから作る弱いETag
+// 一覧の最新の更新日時（max(updatedAt)）は削除や検索の順位の変化で変わらないことがあるため、返す内容そのものから作る。
+// Last-Modifiedも同じ理由で付けない（If-Modified-Sinceだけを送るクライアントに削除後も304を返してしまうため）。
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// WriteJSON はvをJSONにし、Cache-Control・ETagを付けて200で書き込む
+// リクエストのIf-None-MatchがETagと一致した場合はボディを書き込まずに304を返し、notModifiedをtrueにする。
+// JSONにできない場合は何も書き込まずにエラーを返す（書き込みの失敗は接続の切断のため無視する）。
+func WriteJSON(w http.ResponseWriter, r *http.Request, v any) (notModified bool, err error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	etag := ETag(body)
+	header := w.Header()
+	header.Set("Cache-Control", CacheControl)
+	header.Set("ETag", etag)
+
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true, nil
+	}
+
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+	return false, nil
+}
+
+// etagMatches はIf-None-Matchのいずれかのタグがetagと弱い比較で一致するか
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+)
+
+// requestIDField はログに出力するリクエストIDのフィールド名
+const requestIDField = "requestId"
+
+// WithRequestID はリクエストIDと、それをフィールドに持つロガーをctxに設定する
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey, requestID)
+	return WithLogger(ctx, loggerFrom(ctx).With(zap.String(requestIDField, requestID)))
+}
+
+// RequestID はctxのリクエストIDを返す（設定されていない場合は空文字）
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithLogger はctxにロガーを設定する
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext はctxのロガー（リクエストIDなどを含む）にトレースのフィールドを付けて返す
+// ctxにロガーがない場合はグローバルロガーを使う
+func FromContext(ctx context.Context) *zap.Logger {
+	logger := loggerFrom(ctx)
+	if fields := TraceFields(ctx); len(fields) > 0 {
+		logger = logger.With(fields...)
+	}
+	return logger
+}
+
+func loggerFrom(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*zap.Logger); ok {
+		return logger
+	}
+	return GetLogger()
+}
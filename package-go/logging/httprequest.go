@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HttpRequest はCloud Loggingの標準httpRequestフィールド構造
+// https://cloud.google.com/logging/docs/reference/v2/rest/v2/LogEntry#HttpRequest
+// サイズ（int64）はLogEntryのJSON表現に合わせて10進数の文字列、LatencyはDurationの "1.234s" 形式で出力する
+type HttpRequest struct {
+	RequestMethod string
+	RequestUrl    string
+	RequestSize   int64 // 不明な場合は負の値（出力しない）
+	Status        int
+	ResponseSize  int64 // 不明な場合は負の値（出力しない）
+	UserAgent     string
+	RemoteIp      string
+	ServerIp      string
+	Referer       string
+	Latency       time.Duration
+	Protocol      string
+}
+
+// MarshalLogObject はLogEntryの仕様に従ってhttpRequestをエンコードする
+func (r HttpRequest) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	addString := func(key, value string) {
+		if value != "" {
+			enc.AddString(key, value)
+		}
+	}
+
+	addString("requestMethod", r.RequestMethod)
+	addString("requestUrl", r.RequestUrl)
+	if r.RequestSize >= 0 {
+		enc.AddString("requestSize", strconv.FormatInt(r.RequestSize, 10))
+	}
+	if r.Status != 0 {
+		enc.AddInt("status", r.Status)
+	}
+	if r.ResponseSize >= 0 {
+		enc.AddString("responseSize", strconv.FormatInt(r.ResponseSize, 10))
+	}
+	addString("userAgent", r.UserAgent)
+	addString("remoteIp", r.RemoteIp)
+	addString("serverIp", r.ServerIp)
+	addString("referer", r.Referer)
+	enc.AddString("latency", FormatLatency(r.Latency))
+	addString("protocol", r.Protocol)
+	return nil
+}
+
+// Severity はステータスコードに応じたログの重要度（5xxはERROR、4xxはWARNING、それ以外はINFO）
+func (r HttpRequest) Severity() zapcore.Level {
+	switch {
+	case r.Status >= 500:
+		return zapcore.ErrorLevel
+	case r.Status >= 400:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// FormatLatency はDurationをLogEntryのJSON表現（"1.234s" のような秒数、小数部は最大9桁）に変換
+func FormatLatency(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// LogHttpRequest はHTTPリクエスト情報をCloud Logging形式でログ出力（ctxのリクエストID・トレースを含む）
+// 重要度はステータスコードから決める（ミドルウェアのスタックトレースは有用でないため付けない）
+func LogHttpRequest(ctx context.Context, msg string, req HttpRequest, fields ...zap.Field) {
+	allFields := append(fields, zap.Object("httpRequest", req))
+	FromContext(ctx).
+		WithOptions(zap.AddCallerSkip(1), zap.AddStacktrace(zapcore.FatalLevel)).
+		Log(req.Severity(), msg, allFields...)
+}
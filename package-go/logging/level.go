@@ -0,0 +1,175 @@
+package logging
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 名前付きロガー（パッケージの層ごと）の名前
+const (
+	LoggerHandler  = "handler"
+	LoggerService  = "service"
+	LoggerDatabase = "database"
+)
+
+// LoggerNames は個別にレベルを設定できる名前付きロガー
+var LoggerNames = []string{LoggerHandler, LoggerService, LoggerDatabase}
+
+// levelRegistry はルートと名前付きロガーのログレベルを実行中に変更できるよう保持する
+// 名前付きロガーにレベルが設定されていない場合はルートのレベルに従う
+type levelRegistry struct {
+	root zap.AtomicLevel
+
+	mu    sync.RWMutex
+	named map[string]zap.AtomicLevel
+}
+
+func newLevelRegistry(root zapcore.Level) *levelRegistry {
+	return &levelRegistry{
+		root:  zap.NewAtomicLevelAt(root),
+		named: make(map[string]zap.AtomicLevel),
+	}
+}
+
+var levels = newLevelRegistry(zapcore.InfoLevel)
+
+// enabler はnameのロガーの現在のレベルで判定するLevelEnablerを返す
+func (r *levelRegistry) enabler(name string) zapcore.LevelEnabler {
+	return zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		r.mu.RLock()
+		level, ok := r.named[name]
+		r.mu.RUnlock()
+		if ok {
+			return level.Enabled(l)
+		}
+		return r.root.Enabled(l)
+	})
+}
+
+func (r *levelRegistry) set(name string, level zapcore.Level) {
+	if name == "" {
+		r.root.SetLevel(level)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if atomic, ok := r.named[name]; ok {
+		atomic.SetLevel(level)
+		return
+	}
+	r.named[name] = zap.NewAtomicLevelAt(level)
+}
+
+func (r *levelRegistry) reset(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.named, name)
+}
+
+// levelCore はlevelRegistryのレベルでエントリーを絞り込むzapcore.Core
+// 内側のCoreは全レベルを受け付け、ロガーごとのレベルはこのラッパーで判定する
+type levelCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.enabler.Enabled(l)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// Named はloggerに名前を付け、その名前のログレベルで出力するロガーを返す
+// リクエストIDなど、loggerに付いているフィールドは引き継ぐ
+func Named(logger *zap.Logger, name string) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			core = lc.Core
+		}
+		return &levelCore{Core: core, enabler: levels.enabler(name)}
+	})).Named(name)
+}
+
+// SetLevel はログレベルを変更する。nameが空の場合はルート（名前付きロガーで個別に設定していないものを含む）のレベル
+func SetLevel(name, level string) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.set(name, parsed)
+	return nil
+}
+
+// ResetLevel は名前付きロガーの個別のレベルを解除し、ルートのレベルに従わせる
+func ResetLevel(name string) {
+	levels.reset(name)
+}
+
+// Levels は現在のルートのレベルと、個別に設定された名前付きロガーのレベルを返す
+func Levels() (root string, named map[string]string) {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+
+	named = make(map[string]string, len(levels.named))
+	for name, level := range levels.named {
+		named[name] = level.Level().String()
+	}
+	return levels.root.Level().String(), named
+}
+
+// ParseLevel はログレベルの文字列を変換する（debug, info, warn/warning, error）
+func ParseLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "warn", "warning":
+		return zapcore.WarnLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("invalid log level %q (must be debug, info, warn or error)", level)
+	}
+}
+
+// parseNamedLevels は "handler=debug" 形式の指定を名前とレベルに変換
+func parseNamedLevels(specs []string) (map[string]zapcore.Level, error) {
+	named := make(map[string]zapcore.Level, len(specs))
+	var invalid []string
+	for _, spec := range specs {
+		name, level, ok := strings.Cut(spec, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !slices.Contains(LoggerNames, name) {
+			invalid = append(invalid, spec)
+			continue
+		}
+		parsed, err := ParseLevel(level)
+		if err != nil {
+			invalid = append(invalid, spec)
+			continue
+		}
+		named[name] = parsed
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, fmt.Errorf("invalid logger levels %q (must be name=level with name one of %s)", invalid, strings.Join(LoggerNames, ", "))
+	}
+	return named, nil
+}
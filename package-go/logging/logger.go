@@ -6,6 +6,8 @@ import (
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"sample-micro-service-api/package-go/config"
 )
 
 var globalLogger *zap.Logger
@@ -16,25 +18,39 @@ type LogConfig struct {
 	Environment string // development, production
 	Service     string
 	Version     string
-}
+	ProjectID   string // Google CloudのプロジェクトID（ログとトレースの関連付けに使う）
 
-// InitFromEnv は環境変数からログを初期化する便利関数
-func InitFromEnv() error {
-	env := os.Getenv("APP_ENV")
-	if env == "" {
-		env = "development"
-	}
-	
-	logLevel := os.Getenv("LOG_LEVEL")
-	if logLevel == "" {
-		logLevel = "info"
-	}
+	// サンプリング（1秒ごと・重要度とメッセージの組ごとに、最初のSamplingInitial件を出力し、以降はSamplingThereafter件に1件を出力）
+	// SamplingInitialが0の場合はサンプリングしない
+	SamplingInitial    int
+	SamplingThereafter int
+
+	// Redaction はメールアドレス・電話番号などの個人情報をログに出さないための設定
+	Redaction RedactionConfig
 
+	// Levels は名前付きロガーごとのレベル（"handler=debug" 形式）。未指定のロガーはLevelに従う
+	Levels []string
+}
+
+// InitFromConfig はアプリケーション設定からログを初期化する便利関数
+func InitFromConfig(cfg *config.Config, service, version string) error {
 	return Init(LogConfig{
-		Level:       logLevel,
-		Environment: env,
-		Service:     "app-service",
-		Version:     "1.0.0",
+		Level:       cfg.Log.Level,
+		Environment: cfg.App.Environment,
+		Service:     service,
+		Version:     version,
+		ProjectID:   cfg.Log.ProjectID,
+
+		SamplingInitial:    cfg.Log.SamplingInitial,
+		SamplingThereafter: cfg.Log.SamplingThereafter,
+
+		Redaction: RedactionConfig{
+			Fields:       cfg.Log.RedactFields,
+			QueryParams:  cfg.Log.RedactQueryParams,
+			MaskPatterns: cfg.Log.RedactPatterns,
+		},
+
+		Levels: cfg.Log.Levels,
 	})
 }
 
@@ -43,13 +59,28 @@ func Init(config LogConfig) error {
 	var logger *zap.Logger
 	var err error
 
+	named, err := parseNamedLevels(config.Levels)
+	if err != nil {
+		return err
+	}
+
 	logger, err = newLogger(config)
 
 	if err != nil {
 		return err
 	}
 
+	// 作成済みの名前付きロガーにも反映されるよう、レベルは作り直さずに設定し直す
+	levels.root.SetLevel(parseLogLevel(config.Level))
+	levels.mu.Lock()
+	clear(levels.named)
+	levels.mu.Unlock()
+	for name, level := range named {
+		levels.set(name, level)
+	}
+
 	globalLogger = logger
+	traceProjectID = config.ProjectID
 	return nil
 }
 
@@ -91,13 +122,35 @@ func newLogger(config LogConfig) (*zap.Logger, error) {
 		EncodeCaller:   zapcore.ShortCallerEncoder, // 標準的なcaller形式を使用
 	}
 
-	// Cloud Loggingに最適化されたCore設定
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
+	// Cloud Loggingに最適化されたEncoder（開発環境では読みやすいコンソール形式）
+	encoder := zapcore.NewJSONEncoder(encoderConfig)
+	if config.Environment == "development" {
+		encoder = zapcore.NewConsoleEncoder(developmentEncoderConfig())
+	}
+
+	// レベルの判定は外側のlevelCoreで行うため、ここでは全レベルを受け付ける
+	var core zapcore.Core = zapcore.NewCore(
+		encoder,
 		zapcore.AddSync(os.Stdout), // Cloud Runはstdoutを自動収集
-		parseLogLevel(config.Level),
+		zapcore.DebugLevel,
 	)
 
+	// 個人情報を取り除いてから出力する（サンプリングより内側に置き、間引かれたログは処理しない）
+	core = NewRedactingCore(core, config.Redaction)
+
+	// 大量のリクエストでログの取り込み量が増えすぎないよう、同じ重要度・メッセージのログを間引く
+	// （重要度ごとに数えるため、WARNING・ERRORのアクセスログはINFOのアクセスログとは別に数えられる）
+	if config.SamplingInitial > 0 {
+		thereafter := config.SamplingThereafter
+		if thereafter <= 0 {
+			thereafter = 1
+		}
+		core = zapcore.NewSamplerWithOptions(core, time.Second, config.SamplingInitial, thereafter)
+	}
+
+	// ログレベルは実行中に変更できる（SetLevel）
+	core = &levelCore{Core: core, enabler: levels.enabler("")}
+
 	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
 	
 	// Cloud Loggingで認識される追加フィールド
@@ -109,6 +162,13 @@ func newLogger(config LogConfig) (*zap.Logger, error) {
 	return logger, nil
 }
 
+// developmentEncoderConfig は開発環境のコンソール出力用のEncoder設定（時刻・色付きのレベル・呼び出し元）
+func developmentEncoderConfig() zapcore.EncoderConfig {
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("15:04:05.000")
+	return encoderConfig
+}
 
 // parseLogLevel は文字列からzapcore.Levelに変換
 func parseLogLevel(level string) zapcore.Level {
@@ -179,23 +239,3 @@ func Sync() error {
 	return nil
 }
 
-// HttpRequest はCloud Loggingの標準httpRequestフィールド構造
-type HttpRequest struct {
-	RequestMethod string        `json:"requestMethod,omitempty"`
-	RequestUrl    string        `json:"requestUrl,omitempty"`
-	RequestSize   string        `json:"requestSize,omitempty"`
-	Status        int           `json:"status,omitempty"`
-	ResponseSize  string        `json:"responseSize,omitempty"`
-	UserAgent     string        `json:"userAgent,omitempty"`
-	RemoteIp      string        `json:"remoteIp,omitempty"`
-	ServerIp      string        `json:"serverIp,omitempty"`
-	Referer       string        `json:"referer,omitempty"`
-	Latency       string        `json:"latency,omitempty"`
-	Protocol      string        `json:"protocol,omitempty"`
-}
-
-// LogHttpRequest はHTTPリクエスト情報をCloud Logging形式でログ出力
-func LogHttpRequest(msg string, req HttpRequest, fields ...zap.Field) {
-	allFields := append(fields, zap.Any("httpRequest", req))
-	GetLogger().Info(msg, allFields...)
-} 
\ No newline at end of file
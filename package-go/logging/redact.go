@@ -0,0 +1,286 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue は拒否リストに一致した値の置き換え文字列
+const redactedValue = "[REDACTED]"
+
+// RedactionConfig はログから個人情報を取り除く設定
+type RedactionConfig struct {
+	Fields       []string // 値を出力しないフィールド名（大文字・小文字は区別しない。入れ子のオブジェクトのキーも対象）
+	QueryParams  []string // requestUrl・refererなどのURLから値を取り除くクエリパラメータ名
+	MaskPatterns bool     // 文字列中のメールアドレス・電話番号をマスクするか
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@([A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)+)`)
+	// 国内の電話番号（ハイフン区切り・ハイフンなし）と+81形式
+	phonePattern = regexp.MustCompile(`(?:\+81[-\s]?\d{1,4}[-\s]?\d{1,4}[-\s]?\d{3,4}|\b0\d{1,4}-\d{1,4}-\d{3,4}\b|\b0\d{9,10}\b)`)
+)
+
+// urlKeys はクエリパラメータを取り除く対象のフィールド名
+var urlKeys = map[string]bool{
+	"requesturl": true,
+	"referer":    true,
+	"url":        true,
+}
+
+// redactor は拒否リストとパターンに従って値を置き換える
+type redactor struct {
+	fields       map[string]bool
+	queryParams  map[string]bool
+	maskPatterns bool
+}
+
+func newRedactor(config RedactionConfig) *redactor {
+	r := &redactor{
+		fields:       make(map[string]bool),
+		queryParams:  make(map[string]bool),
+		maskPatterns: config.MaskPatterns,
+	}
+	for _, f := range config.Fields {
+		r.fields[strings.ToLower(strings.TrimSpace(f))] = true
+	}
+	for _, p := range config.QueryParams {
+		r.queryParams[strings.ToLower(strings.TrimSpace(p))] = true
+	}
+	return r
+}
+
+func (r *redactor) deniedKey(key string) bool {
+	return r.fields[strings.ToLower(key)]
+}
+
+// mask は文字列中のメールアドレス（ドメインは残す）と電話番号をマスクする
+func (r *redactor) mask(s string) string {
+	if !r.maskPatterns {
+		return s
+	}
+	s = emailPattern.ReplaceAllString(s, "***@$1")
+	return phonePattern.ReplaceAllString(s, "[PHONE]")
+}
+
+// redactString はキーと値に応じて文字列を置き換える
+func (r *redactor) redactString(key, value string) string {
+	if r.deniedKey(key) {
+		return redactedValue
+	}
+	if urlKeys[strings.ToLower(key)] {
+		value = r.redactURL(value)
+	}
+	return r.mask(value)
+}
+
+// redactURL はURLのクエリ文字列から拒否リストのパラメータの値を取り除く（パラメータの順序は保つ）
+func (r *redactor) redactURL(raw string) string {
+	i := strings.IndexByte(raw, '?')
+	if i < 0 || len(r.queryParams) == 0 {
+		return raw
+	}
+
+	pairs := strings.Split(raw[i+1:], "&")
+	for j, pair := range pairs {
+		name, _, _ := strings.Cut(pair, "=")
+		decoded, err := url.QueryUnescape(name)
+		if err != nil {
+			decoded = name
+		}
+		if r.queryParams[strings.ToLower(decoded)] {
+			pairs[j] = name + "=" + url.QueryEscape(redactedValue)
+		}
+	}
+	return raw[:i+1] + strings.Join(pairs, "&")
+}
+
+// redactValue はJSONとして解釈した値（map・slice・文字列）を再帰的に置き換える
+func (r *redactor) redactValue(key string, v interface{}) interface{} {
+	if r.deniedKey(key) {
+		return redactedValue
+	}
+	switch v := v.(type) {
+	case string:
+		return r.redactString(key, v)
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = r.redactValue(k, child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = r.redactValue(key, child)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// redactFields はフィールドの種類ごとに値を置き換えたコピーを返す
+func (r *redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		redacted[i] = r.redactField(f)
+	}
+	return redacted
+}
+
+func (r *redactor) redactField(f zapcore.Field) zapcore.Field {
+	if r.deniedKey(f.Key) && f.Type != zapcore.SkipType && f.Type != zapcore.NamespaceType {
+		return zap.String(f.Key, redactedValue)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		return zap.String(f.Key, r.redactString(f.Key, f.String))
+	case zapcore.ByteStringType:
+		return zap.String(f.Key, r.redactString(f.Key, string(f.Interface.([]byte))))
+	case zapcore.ErrorType:
+		// DBのエラー（一意制約違反など）には値が含まれることがある
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return zap.String(f.Key, r.mask(err.Error()))
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(interface{ String() string }); ok && s != nil {
+			return zap.String(f.Key, r.redactString(f.Key, s.String()))
+		}
+	case zapcore.ObjectMarshalerType:
+		return zap.Object(f.Key, redactingObject{inner: f.Interface.(zapcore.ObjectMarshaler), r: r})
+	case zapcore.ArrayMarshalerType:
+		// zap.Strings・zap.Errorsなどの配列（宛先のメールアドレスの一覧など）
+		return zap.Array(f.Key, redactingArray{key: f.Key, inner: f.Interface.(zapcore.ArrayMarshaler), r: r})
+	case zapcore.ReflectType:
+		return zap.Any(f.Key, r.redactReflected(f.Key, f.Interface))
+	}
+	return f
+}
+
+// redactReflected は任意の値をJSONに変換してから置き換える（変換できない値は出力しない）
+func (r *redactor) redactReflected(key string, v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redactedValue
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return redactedValue
+	}
+	return r.redactValue(key, decoded)
+}
+
+// redactingObject はObjectMarshalerの出力を置き換えるラッパー
+type redactingObject struct {
+	inner zapcore.ObjectMarshaler
+	r     *redactor
+}
+
+func (o redactingObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.inner.MarshalLogObject(&redactingEncoder{ObjectEncoder: enc, r: o.r})
+}
+
+// redactingEncoder は文字列・入れ子のオブジェクトと配列・任意の値を置き換えてから書き込むObjectEncoder
+type redactingEncoder struct {
+	zapcore.ObjectEncoder
+	r *redactor
+}
+
+func (e *redactingEncoder) AddString(key, value string) {
+	e.ObjectEncoder.AddString(key, e.r.redactString(key, value))
+}
+
+func (e *redactingEncoder) AddByteString(key string, value []byte) {
+	e.ObjectEncoder.AddString(key, e.r.redactString(key, string(value)))
+}
+
+func (e *redactingEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	if e.r.deniedKey(key) {
+		e.ObjectEncoder.AddString(key, redactedValue)
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, redactingObject{inner: marshaler, r: e.r})
+}
+
+func (e *redactingEncoder) AddReflected(key string, value interface{}) error {
+	return e.ObjectEncoder.AddReflected(key, e.r.redactReflected(key, value))
+}
+
+func (e *redactingEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	if e.r.deniedKey(key) {
+		e.ObjectEncoder.AddString(key, redactedValue)
+		return nil
+	}
+	return e.ObjectEncoder.AddArray(key, redactingArray{key: key, inner: marshaler, r: e.r})
+}
+
+// redactingArray はArrayMarshalerの要素を置き換えるラッパー（要素にはkeyの規則を適用する）
+type redactingArray struct {
+	key   string
+	inner zapcore.ArrayMarshaler
+	r     *redactor
+}
+
+func (a redactingArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.inner.MarshalLogArray(&redactingArrayEncoder{ArrayEncoder: enc, key: a.key, r: a.r})
+}
+
+// redactingArrayEncoder は文字列・入れ子のオブジェクトと配列・任意の値の要素を置き換えてから書き込むArrayEncoder
+type redactingArrayEncoder struct {
+	zapcore.ArrayEncoder
+	key string
+	r   *redactor
+}
+
+func (e *redactingArrayEncoder) AppendString(value string) {
+	e.ArrayEncoder.AppendString(e.r.redactString(e.key, value))
+}
+
+func (e *redactingArrayEncoder) AppendByteString(value []byte) {
+	e.ArrayEncoder.AppendString(e.r.redactString(e.key, string(value)))
+}
+
+func (e *redactingArrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(redactingObject{inner: marshaler, r: e.r})
+}
+
+func (e *redactingArrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(redactingArray{key: e.key, inner: marshaler, r: e.r})
+}
+
+func (e *redactingArrayEncoder) AppendReflected(value interface{}) error {
+	return e.ArrayEncoder.AppendReflected(e.r.redactReflected(e.key, value))
+}
+
+// redactingCore はエントリーを書き込む前にメッセージとフィールドから個人情報を取り除くzapcore.Core
+type redactingCore struct {
+	zapcore.Core
+	r *redactor
+}
+
+// NewRedactingCore はcoreに書き込む前に個人情報を取り除くCoreを返す
+// With で付けたフィールドも対象になる
+func NewRedactingCore(core zapcore.Core, config RedactionConfig) zapcore.Core {
+	return &redactingCore{Core: core, r: newRedactor(config)}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.r.redactFields(fields)), r: c.r}
+}
+
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.r.mask(ent.Message)
+	return c.Core.Write(ent, c.r.redactFields(fields))
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	testEmail = "taro.yamada@example.jp"
+	testPhone = "03-1234-5678"
+)
+
+// newTestLogger はJSONでbufに書き込むロガーを既定の設定（config.LogConfigの既定値）のNewRedactingCoreで包んで返す
+func newTestLogger(buf *bytes.Buffer) *zap.Logger {
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(buf),
+		zapcore.DebugLevel,
+	)
+	return zap.New(NewRedactingCore(core, RedactionConfig{
+		Fields:       []string{"email", "mailAddress", "telephone", "phone", "tel"},
+		QueryParams:  []string{"email", "mailAddress", "telephone", "phone", "tel"},
+		MaskPatterns: true,
+	}))
+}
+
+type contact struct {
+	Name    string `json:"name"`
+	Contact struct {
+		Telephone string `json:"telephone"`
+	} `json:"contact"`
+}
+
+type recipients []string
+
+func (r recipients) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, s := range r {
+		enc.AppendString(s)
+	}
+	return nil
+}
+
+type notification struct {
+	to recipients
+}
+
+func (n notification) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return enc.AddArray("to", n.to)
+}
+
+func TestRedactingCore(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(logger *zap.Logger)
+		keep string // 残るべき値（空の場合は確認しない）
+	}{
+		{
+			name: "string field",
+			log: func(logger *zap.Logger) {
+				logger.Info("created", zap.String("mailAddress", testEmail), zap.String("telephone", testPhone))
+			},
+		},
+		{
+			name: "nested struct with zap.Any",
+			log: func(logger *zap.Logger) {
+				var c contact
+				c.Name = "住民基本台帳システム"
+				c.Contact.Telephone = testPhone
+				logger.Info("created", zap.Any("system", c))
+			},
+			keep: "住民基本台帳システム",
+		},
+		{
+			name: "nested map with zap.Any",
+			log: func(logger *zap.Logger) {
+				logger.Info("created", zap.Any("system", map[string]interface{}{
+					"contact": map[string]interface{}{"telephone": testPhone, "mailAddress": testEmail},
+				}))
+			},
+		},
+		{
+			name: "fields attached with With",
+			log: func(logger *zap.Logger) {
+				logger.With(zap.String("mailAddress", testEmail)).With(zap.String("phone", testPhone)).Info("updated")
+			},
+		},
+		{
+			name: "query parameters in requestUrl",
+			log: func(logger *zap.Logger) {
+				logger.Info("request", zap.Object("httpRequest", HttpRequest{
+					RequestMethod: "GET",
+					RequestUrl:    "/api/v1/systems?email=taro.yamada%40example.jp&telephone=03-1234-5678&limit=10",
+				}))
+			},
+			keep: "limit=10",
+		},
+		{
+			name: "email and phone number in message",
+			log: func(logger *zap.Logger) {
+				logger.Info("sent notification to " + testEmail + " and " + testPhone)
+			},
+			keep: "***@example.jp",
+		},
+		{
+			name: "email in error",
+			log: func(logger *zap.Logger) {
+				logger.Error("failed", zap.Error(errors.New(`duplicate key value violates unique constraint: (mailAddress)=(`+testEmail+`)`)))
+			},
+			keep: "duplicate key value",
+		},
+		{
+			name: "string array",
+			log: func(logger *zap.Logger) {
+				logger.Info("sent", zap.Strings("recipients", []string{testEmail, "hanako@example.jp"}))
+			},
+			keep: "***@example.jp",
+		},
+		{
+			name: "array nested in object",
+			log: func(logger *zap.Logger) {
+				logger.Info("sent", zap.Object("notification", notification{to: recipients{testEmail, testPhone}}))
+			},
+			keep: "[PHONE]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(newTestLogger(&buf))
+
+			out := buf.String()
+			if out == "" {
+				t.Fatal("nothing was logged")
+			}
+			for _, value := range []string{testEmail, "taro.yamada%40example.jp", "hanako@example.jp", testPhone} {
+				if strings.Contains(out, value) {
+					t.Errorf("output contains %q: %s", value, out)
+				}
+			}
+			if tt.keep != "" && !strings.Contains(out, tt.keep) {
+				t.Errorf("output does not contain %q: %s", tt.keep, out)
+			}
+		})
+	}
+}
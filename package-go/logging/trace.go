@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Cloud Loggingがログとトレースを関連付けるための特殊フィールド
+const (
+	traceKey        = "logging.googleapis.com/trace"
+	spanIDKey       = "logging.googleapis.com/spanId"
+	traceSampledKey = "logging.googleapis.com/trace_sampled"
+)
+
+// traceProjectID は logging.googleapis.com/trace の "projects/<ID>/traces/<トレースID>" に使うプロジェクトID
+var traceProjectID string
+
+// TraceFields はctxのスパンをCloud Loggingのトレース関連フィールドに変換する（スパンがない場合は空）
+// プロジェクトIDが未設定の場合、traceにはトレースIDのみを出力する
+func TraceFields(ctx context.Context) []zap.Field {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+
+	traceID := spanContext.TraceID().String()
+	if traceProjectID != "" {
+		traceID = "projects/" + traceProjectID + "/traces/" + traceID
+	}
+
+	return []zap.Field{
+		zap.String(traceKey, traceID),
+		zap.String(spanIDKey, spanContext.SpanID().String()),
+		zap.Bool(traceSampledKey, spanContext.IsSampled()),
+	}
+}
@@ -0,0 +1,147 @@
+// Package metrics はPrometheus形式のメトリクスを定義する
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"sample-micro-service-api/package-go/cache"
+)
+
+// NewRegistry はGoランタイムとプロセスのメトリクスを登録したRegistryを作成
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+// Handler は/metrics用のHTTPハンドラーを返す
+func Handler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// RegisterPoolStats はpgxpool.Pool.Stat()の接続プールの値（pgxpool_*{db_name="name"}）を登録する
+func RegisterPoolStats(registry prometheus.Registerer, pool *pgxpool.Pool, dbName string) {
+	registry.MustRegister(newPoolStatsCollector(pool, dbName))
+}
+
+// RegisterCache はキャッシュのヒット・ミス・追い出しの件数とエントリ数（cache_*{cache="name"}）を登録する
+// ヒット率は rate(cache_hits_total[5m]) / (rate(cache_hits_total[5m]) + rate(cache_misses_total[5m])) で求める
+func RegisterCache(registry prometheus.Registerer, name string, c *cache.Cache) {
+	labels := prometheus.Labels{"cache": name}
+	registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "cache_hits_total",
+			Help:        "Total number of cache lookups served from the cache.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(c.Stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "cache_misses_total",
+			Help:        "Total number of cache lookups that went to the database.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(c.Stats().Misses) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "cache_evictions_total",
+			Help:        "Total number of entries evicted because the cache was full.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(c.Stats().Evictions) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "cache_entries",
+			Help:        "Number of entries currently in the cache.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(c.Stats().Entries) }),
+	)
+}
+
+// HTTPMetrics はHTTPリクエストのメトリクス
+// routeには生のパスではなくルート定義（/api/v1/systems/:id など）を使い、ラベルの種類が増えないようにする
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+	limited  *prometheus.CounterVec
+}
+
+// NewHTTPMetrics はHTTPMetricsを作成してregistryに登録
+func NewHTTPMetrics(registry prometheus.Registerer) *HTTPMetrics {
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency in seconds by method, route template and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served.",
+		}),
+		limited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_rate_limited_total",
+			Help: "Total number of HTTP requests rejected by the rate limiter by method and route template.",
+		}, []string{"method", "route"}),
+	}
+	registry.MustRegister(m.requests, m.duration, m.inFlight, m.limited)
+	return m
+}
+
+// Start はリクエストの開始を記録し、完了時に呼ぶ関数を返す
+func (m *HTTPMetrics) Start() func(method, route string, status int) {
+	start := time.Now()
+	m.inFlight.Inc()
+	return func(method, route string, status int) {
+		m.inFlight.Dec()
+		code := strconv.Itoa(status)
+		m.requests.WithLabelValues(method, route, code).Inc()
+		m.duration.WithLabelValues(method, route, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RateLimited はレート制限で拒否したリクエストを記録する
+func (m *HTTPMetrics) RateLimited(method, route string) {
+	m.limited.WithLabelValues(method, route).Inc()
+}
+
+// QueryMetrics はsqlcクエリごとの実行時間のメトリクス
+type QueryMetrics struct {
+	duration *prometheus.HistogramVec
+}
+
+// NewQueryMetrics はQueryMetricsを作成してregistryに登録
+func NewQueryMetrics(registry prometheus.Registerer) *QueryMetrics {
+	m := &QueryMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Duration of sqlc queries in seconds by query name and outcome.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"query", "status"}),
+	}
+	registry.MustRegister(m.duration)
+	return m
+}
+
+// Hook はdatabase.QueryHookとして使う関数
+func (m *QueryMetrics) Hook(ctx context.Context, name string) (context.Context, func(err error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		status := "ok"
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			status = "error"
+		}
+		m.duration.WithLabelValues(name, status).Observe(time.Since(start).Seconds())
+	}
+}
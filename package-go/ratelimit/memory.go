@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval は満杯に戻ったバケットを削除する間隔
+const sweepInterval = time.Minute
+
+// MemoryStore はプロセス内のメモリにバケットを保持するStore
+// インスタンスごとに独立して制限するため、複数インスタンスで動かす場合の上限はインスタンス数倍になる
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // このバケットが満杯に戻る時刻
+}
+
+// NewMemoryStore はMemoryStoreを作成
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+// Take はkeyのバケットにトークンを補充してから1つ取得する
+func (s *MemoryStore) Take(_ context.Context, key string, policy Policy, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	capacity := float64(policy.Limit)
+	rate := capacity / policy.Period.Seconds() // 1秒あたりに補充されるトークン数
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.last = now
+	}
+
+	result := Result{Limit: policy.Limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+
+	b.full = now.Add(seconds((capacity - b.tokens) / rate))
+	result.Remaining = int(b.tokens)
+	result.Reset = b.full.Sub(now)
+	return result, nil
+}
+
+// sweep は満杯に戻った（削除しても結果が変わらない）バケットを定期的に削除する
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,139 @@
+// Package ratelimit はトークンバケットによるリクエストのレート制限を提供する
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sample-micro-service-api/package-go/config"
+)
+
+// DefaultPolicyName はルート別のポリシーがないルートに適用するポリシーの名前
+const DefaultPolicyName = "default"
+
+// noLimit はルート別のポリシーでレート制限をしないことを表す値
+const noLimit = "none"
+
+// Policy はレート制限のポリシー
+// Period あたり Limit 回のペースでトークンが補充され、最大 Limit 回までの連続したリクエストを許可する
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+// Result はトークンを取得した結果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // 残りのトークン数
+	Reset      time.Duration // バケットが満杯に戻るまでの時間
+	RetryAfter time.Duration // 拒否された場合、次のリクエストが許可されるまでの時間
+}
+
+// Store はクライアントごとのバケットの状態を保持する
+// 複数のインスタンスで制限を共有する場合は、Redis等を使う実装に差し替える
+type Store interface {
+	// Take はkeyのバケットからトークンを1つ取得する
+	Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error)
+}
+
+// Limiter はルートごとのポリシーに従ってレート制限を行う
+type Limiter struct {
+	store         Store
+	enabled       bool
+	defaultPolicy Policy
+	routes        map[string]*Policy // nilはレート制限をしないルート
+}
+
+// NewLimiter は設定からLimiterを作成する
+func NewLimiter(cfg config.RateLimitConfig, store Store) (*Limiter, error) {
+	defaultPolicy, err := ParsePolicy(DefaultPolicyName, cfg.Default)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_DEFAULT: %w", err)
+	}
+
+	routes := make(map[string]*Policy, len(cfg.Routes))
+	for _, entry := range cfg.Routes {
+		route, spec, ok := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+		if !ok || route == "" {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES entry %q: must be \"METHOD /route=limit/period\"", entry)
+		}
+		if strings.TrimSpace(spec) == noLimit {
+			routes[route] = nil
+			continue
+		}
+		policy, err := ParsePolicy(route, spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES entry %q: %w", entry, err)
+		}
+		routes[route] = &policy
+	}
+
+	return &Limiter{
+		store:         store,
+		enabled:       cfg.Enabled,
+		defaultPolicy: defaultPolicy,
+		routes:        routes,
+	}, nil
+}
+
+// ParsePolicy は "60/1m"（1分あたり60回）の形式のポリシーを読み込む
+func ParsePolicy(name, spec string) (Policy, error) {
+	limit, period, ok := strings.Cut(strings.TrimSpace(spec), "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("%q must be limit/period (e.g. 60/1m)", spec)
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 1 {
+		return Policy{}, fmt.Errorf("%q: limit must be a positive integer", spec)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Policy{}, fmt.Errorf("%q: period must be a positive duration", spec)
+	}
+
+	return Policy{Name: name, Limit: n, Period: d}, nil
+}
+
+// Policy はルート（"GET /api/v1/systems" のようなメソッドとルート定義）に適用するポリシーを返す
+// レート制限をしない場合はfalseを返す
+func (l *Limiter) Policy(method, route string) (Policy, bool) {
+	if !l.enabled {
+		return Policy{}, false
+	}
+	if policy, ok := l.routes[method+" "+route]; ok {
+		if policy == nil {
+			return Policy{}, false
+		}
+		return *policy, true
+	}
+	return l.defaultPolicy, true
+}
+
+// Take はクライアント（key）のpolicyのバケットからトークンを1つ取得する
+// defaultポリシーのバケットは、ルート別のポリシーがないルートの間で共有される
+func (l *Limiter) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	return l.store.Take(ctx, policy.Name+"|"+key, policy, time.Now())
+}
+
+type contextKey int
+
+const subjectKey contextKey = iota
+
+// WithSubject は認証済みのユーザー（レート制限のキー）をctxに設定する
+// 認証ミドルウェアが設定し、未設定の場合はクライアントのIPアドレスごとに制限する
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey, subject)
+}
+
+// Subject はctxの認証済みのユーザーを返す（設定されていない場合は空文字）
+func Subject(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectKey).(string)
+	return subject
+}
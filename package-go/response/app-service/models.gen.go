@@ -9,6 +9,10 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	AdminTokenScopes = "adminToken.Scopes"
+)
+
 // CommonError defines model for common.Error.
 type CommonError struct {
 	// Detail エラーの詳細説明
@@ -23,7 +27,7 @@ type CommonError struct {
 		Message *string `json:"message,omitempty"`
 	} `json:"errors,omitempty"`
 
-	// Instance 問題の一意識別子 (URIなど)
+	// Instance 問題の一意識別子 (URIなど)。リクエストID（X-Request-ID）を返す
 	Instance *string `json:"instance,omitempty"`
 
 	// Status HTTPステータスコード
@@ -32,7 +36,7 @@ type CommonError struct {
 	// Title エラーの短いタイトル
 	Title string `json:"title"`
 
-	// TraceId リクエストトレース用ID
+	// TraceId リクエストトレース用ID（W3C Trace ContextのトレースID。traceparentで渡された場合はそのトレースを引き継ぐ）。トレースがない場合はリクエストID
 	TraceId *string `json:"traceId,omitempty"`
 
 	// Type 問題の種類を表すURI
@@ -41,7 +45,68 @@ type CommonError struct {
 
 // ModelHealthCheck defines model for model.HealthCheck.
 type ModelHealthCheck struct {
+	// Checks Per-dependency readiness results, in registration order (readiness only)
+	Checks *[]struct {
+		// Error Why the check failed
+		Error *string `json:"error,omitempty"`
+
+		// LatencyMs How long the check took in milliseconds
+		LatencyMs float64 `json:"latencyMs"`
+
+		// Name The name of the check
+		Name string `json:"name"`
+
+		// Status The result of the check (ok or fail)
+		Status string `json:"status"`
+	} `json:"checks,omitempty"`
+
+	// Service The name of the service
+	Service *string `json:"service,omitempty"`
+
+	// Status The overall status (ok, fail, or draining while the server is shutting down)
 	Status string `json:"status"`
+
+	// Timestamp The time the status was evaluated
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// ModelLogLevels defines model for model.LogLevels.
+type ModelLogLevels struct {
+	// Level The root log level, used by loggers without their own level (debug, info, warn or error)
+	Level string `json:"level"`
+
+	// Loggers Loggers (handler, service, database) with their own level
+	Loggers map[string]string `json:"loggers"`
+}
+
+// ModelSearchResult defines model for model.SearchResult.
+type ModelSearchResult struct {
+	// Query The search query as received
+	Query string `json:"query"`
+
+	// Sections Non-empty result sections, ordered by their best score
+	Sections []struct {
+		// Items Matches in the section, ordered by score
+		Items []struct {
+			// Highlight HTML-escaped title or subtitle with the matched parts wrapped in mark tags
+			Highlight string `json:"highlight"`
+
+			// Id The ID of the entity (a UUID, or a 6-digit code for local governments)
+			Id string `json:"id"`
+
+			// Score Relevance score between 0 and 1 (higher is better)
+			Score float32 `json:"score"`
+
+			// Subtitle Supplementary text such as the vendor name or the reading of the name
+			Subtitle *string `json:"subtitle"`
+
+			// Title The display name of the entity
+			Title string `json:"title"`
+		} `json:"items"`
+
+		// Type The entity type of the section (system, project, group or localGovernment)
+		Type string `json:"type"`
+	} `json:"sections"`
 }
 
 // ModelSystem defines model for model.System.
@@ -49,6 +114,9 @@ type ModelSystem struct {
 	// CreatedAt The timestamp when the system was created
 	CreatedAt time.Time `json:"createdAt"`
 
+	// Highlight HTML-escaped snippet with matches wrapped in <mark> (only present when searching with q)
+	Highlight *string `json:"highlight,omitempty"`
+
 	// Id The ID of the system
 	Id openapi_types.UUID `json:"id"`
 
@@ -61,9 +129,15 @@ type ModelSystem struct {
 	// Remark Additional remarks or notes about the system
 	Remark *string `json:"remark"`
 
+	// Score Similarity to the search query (only present when searching with q)
+	Score *float32 `json:"score,omitempty"`
+
 	// SystemName The name of the system
 	SystemName string `json:"systemName"`
 
+	// SystemNameKana The reading of the system name in katakana, used for search
+	SystemNameKana *string `json:"systemNameKana"`
+
 	// Telephone The telephone number associated with the system
 	Telephone *string `json:"telephone"`
 
@@ -71,11 +145,162 @@ type ModelSystem struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// ModelSystemImportResult defines model for model.SystemImportResult.
+type ModelSystemImportResult struct {
+	// Committed Whether the changes were committed to the database
+	Committed bool `json:"committed"`
+
+	// Creates Number of rows that create a new system
+	Creates int32 `json:"creates"`
+
+	// DryRun Whether the import was run in dry-run mode
+	DryRun bool `json:"dryRun"`
+
+	// Errors Number of rows with validation errors
+	Errors int32 `json:"errors"`
+
+	// Rows Per-row results in file order
+	Rows []struct {
+		// Action What the row does to the database (create, update or error)
+		Action string `json:"action"`
+
+		// Errors Validation errors for the row
+		Errors []struct {
+			// Field The column the error relates to
+			Field string `json:"field"`
+
+			// Message The error message
+			Message string `json:"message"`
+		} `json:"errors"`
+
+		// Id The ID of the updated system, or of the created system once committed
+		Id *openapi_types.UUID `json:"id"`
+
+		// Line Line number in the file (the header is line 1)
+		Line int32 `json:"line"`
+
+		// LocalGovernmentId The resolved local government ID
+		LocalGovernmentId *string `json:"localGovernmentId"`
+
+		// SystemName The name of the system in the row
+		SystemName string `json:"systemName"`
+	} `json:"rows"`
+
+	// Total Number of data rows in the file
+	Total int32 `json:"total"`
+
+	// Updates Number of rows that update an existing system
+	Updates int32 `json:"updates"`
+}
+
+// ModelVendor defines model for model.Vendor.
+type ModelVendor struct {
+	// CityName The city of the registered address (from the NTA data)
+	CityName *string `json:"cityName"`
+
+	// CloseDate The date the corporation was closed, if any (from the NTA data)
+	CloseDate *openapi_types.Date `json:"closeDate"`
+
+	// CorporateNumber The 13-digit corporate number assigned by the National Tax Agency
+	CorporateNumber string `json:"corporateNumber"`
+
+	// NtaUpdatedAt The update date of the NTA data, or null if the vendor has not been enriched
+	NtaUpdatedAt *openapi_types.Date `json:"ntaUpdatedAt"`
+
+	// PostCode The postal code of the registered address (from the NTA data)
+	PostCode *string `json:"postCode"`
+
+	// PrefectureName The prefecture of the registered address (from the NTA data)
+	PrefectureName *string `json:"prefectureName"`
+
+	// Projects Projects that list this vendor
+	Projects []struct {
+		// Id The ID of the project
+		Id openapi_types.UUID `json:"id"`
+
+		// LocalGovernmentId The local government ID of the project
+		LocalGovernmentId string `json:"localGovernmentId"`
+
+		// ProjectName The name of the project
+		ProjectName string `json:"projectName"`
+	} `json:"projects"`
+
+	// StreetNumber The street address of the registered address (from the NTA data)
+	StreetNumber *string `json:"streetNumber"`
+
+	// Systems Systems related to the vendor's projects
+	Systems []struct {
+		// Id The ID of the system
+		Id openapi_types.UUID `json:"id"`
+
+		// SystemName The name of the system
+		SystemName string `json:"systemName"`
+	} `json:"systems"`
+
+	// VendorName The vendor name, taken from the NTA data when available and otherwise from the latest project record
+	VendorName string `json:"vendorName"`
+
+	// VendorNameKana The reading of the vendor name (from the NTA data)
+	VendorNameKana *string `json:"vendorNameKana"`
+}
+
+// UpdateLogLevelJSONBody defines parameters for UpdateLogLevel.
+type UpdateLogLevelJSONBody struct {
+	// Level debug, info, warn, error, or inherit (named loggers only)
+	Level string `json:"level"`
+
+	// Logger The named logger to change (omit to change the root level)
+	Logger *string `json:"logger,omitempty"`
+}
+
+// SearchParams defines parameters for Search.
+type SearchParams struct {
+	// Q Search query
+	Q string `form:"q" json:"q"`
+
+	// Limit Maximum number of items per section
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// IfNoneMatch ETag of a previous response; 304 is returned if the results have not changed
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// GetSystemsParams defines parameters for GetSystems.
+type GetSystemsParams struct {
+	// Q Fuzzy search on the system name and its reading. Full-width/half-width and
+	// katakana/hiragana differences are ignored, and results are ranked by similarity.
+	Q *string `form:"q,omitempty" json:"q,omitempty"`
+
+	// Limit Maximum number of results when searching with q (1-200)
+	Limit *int32 `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// SystemName Filter by system name (partial match)
+	SystemName *string `form:"systemName,omitempty" json:"systemName,omitempty"`
+
+	// Email Filter by email address (exact match)
+	Email *openapi_types.Email `form:"email,omitempty" json:"email,omitempty"`
+
+	// LocalGovernmentId Filter by local government ID (exact match)
+	LocalGovernmentId *string `form:"localGovernmentId,omitempty" json:"localGovernmentId,omitempty"`
+
+	// Format Output format, one of json, csv, xlsx or ndjson (takes precedence over the Accept header)
+	Format *string `form:"format,omitempty" json:"format,omitempty"`
+
+	// Headers Header language for csv and xlsx, en (field names) or ja (Japanese labels)
+	Headers *string `form:"headers,omitempty" json:"headers,omitempty"`
+
+	// IfNoneMatch ETag of a previous JSON response; 304 is returned if the list has not changed
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
 // CreateSystemJSONBody defines parameters for CreateSystem.
 type CreateSystemJSONBody struct {
 	// CreatedAt The timestamp when the system was created
 	CreatedAt time.Time `json:"createdAt"`
 
+	// Highlight HTML-escaped snippet with matches wrapped in <mark> (only present when searching with q)
+	Highlight *string `json:"highlight,omitempty"`
+
 	// Id The ID of the system
 	Id openapi_types.UUID `json:"id"`
 
@@ -88,9 +313,15 @@ type CreateSystemJSONBody struct {
 	// Remark Additional remarks or notes about the system
 	Remark *string `json:"remark"`
 
+	// Score Similarity to the search query (only present when searching with q)
+	Score *float32 `json:"score,omitempty"`
+
 	// SystemName The name of the system
 	SystemName string `json:"systemName"`
 
+	// SystemNameKana The reading of the system name in katakana, used for search
+	SystemNameKana *string `json:"systemNameKana"`
+
 	// Telephone The telephone number associated with the system
 	Telephone *string `json:"telephone"`
 
@@ -98,11 +329,29 @@ type CreateSystemJSONBody struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// ImportSystemsMultipartBody defines parameters for ImportSystems.
+type ImportSystemsMultipartBody struct {
+	// File CSV or XLSX file whose first row is the header
+	File openapi_types.File `json:"file"`
+}
+
+// ImportSystemsParams defines parameters for ImportSystems.
+type ImportSystemsParams struct {
+	// DryRun Validate only and return the report without writing to the database
+	DryRun *bool `form:"dryRun,omitempty" json:"dryRun,omitempty"`
+
+	// Encoding Character encoding of a CSV file, one of auto, utf-8 or shift_jis (ignored for XLSX)
+	Encoding *string `form:"encoding,omitempty" json:"encoding,omitempty"`
+}
+
 // UpdateSystemJSONBody defines parameters for UpdateSystem.
 type UpdateSystemJSONBody struct {
 	// CreatedAt The timestamp when the system was created
 	CreatedAt time.Time `json:"createdAt"`
 
+	// Highlight HTML-escaped snippet with matches wrapped in <mark> (only present when searching with q)
+	Highlight *string `json:"highlight,omitempty"`
+
 	// Id The ID of the system
 	Id openapi_types.UUID `json:"id"`
 
@@ -115,9 +364,15 @@ type UpdateSystemJSONBody struct {
 	// Remark Additional remarks or notes about the system
 	Remark *string `json:"remark"`
 
+	// Score Similarity to the search query (only present when searching with q)
+	Score *float32 `json:"score,omitempty"`
+
 	// SystemName The name of the system
 	SystemName string `json:"systemName"`
 
+	// SystemNameKana The reading of the system name in katakana, used for search
+	SystemNameKana *string `json:"systemNameKana"`
+
 	// Telephone The telephone number associated with the system
 	Telephone *string `json:"telephone"`
 
@@ -125,8 +380,23 @@ type UpdateSystemJSONBody struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// GetVendorsParams defines parameters for GetVendors.
+type GetVendorsParams struct {
+	// CorporateNumber Filter by corporate number (13 digits with a valid check digit; hyphens and full-width digits are accepted)
+	CorporateNumber *string `form:"corporateNumber,omitempty" json:"corporateNumber,omitempty"`
+
+	// IfNoneMatch ETag of a previous response; 304 is returned if the list has not changed
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// UpdateLogLevelJSONRequestBody defines body for UpdateLogLevel for application/json ContentType.
+type UpdateLogLevelJSONRequestBody UpdateLogLevelJSONBody
+
 // CreateSystemJSONRequestBody defines body for CreateSystem for application/json ContentType.
 type CreateSystemJSONRequestBody CreateSystemJSONBody
 
+// ImportSystemsMultipartRequestBody defines body for ImportSystems for multipart/form-data ContentType.
+type ImportSystemsMultipartRequestBody ImportSystemsMultipartBody
+
 // UpdateSystemJSONRequestBody defines body for UpdateSystem for application/json ContentType.
 type UpdateSystemJSONRequestBody UpdateSystemJSONBody
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"html"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// カタカナ（ァ〜ヶ）とひらがな（ぁ〜ゖ）のコードポイントの差
+const (
+	katakanaFirst = 'ァ'
+	katakanaLast  = 'ヶ'
+	kanaOffset    = 'ァ' - 'ぁ'
+)
+
+// Normalize は検索用に文字列を正規化する
+// NFKC正規化（全角英数・半角カナの統一）の後にカタカナをひらがなへ寄せ、小文字化する。
+// DBの public.normalize_search_text（migrations/002_system_search.up.sql）と同じ規則。
+func Normalize(s string) string {
+	return strings.Map(foldKana, strings.ToLower(norm.NFKC.String(s)))
+}
+
+func foldKana(r rune) rune {
+	if r >= katakanaFirst && r <= katakanaLast {
+		return r - kanaOffset
+	}
+	return r
+}
+
+// isVoicedMark は直前の文字と合成される濁点・半濁点かどうか
+func isVoicedMark(r rune) bool {
+	switch r {
+	case '゙', '゚', 'ﾞ', 'ﾟ':
+		return true
+	}
+	return false
+}
+
+// Highlight はtextのうち正規化後にqueryと一致する箇所を<mark>で囲んだHTML断片を返す
+// text自体はHTMLエスケープされる。一致箇所がなければfalseを返す。
+func Highlight(text, query string) (string, bool) {
+	normalizedQuery := Normalize(strings.TrimSpace(query))
+	if normalizedQuery == "" {
+		return html.EscapeString(text), false
+	}
+
+	// 元の文字列を（濁点等を含む）文字単位に区切り、正規化後のバイト位置から元の位置を引けるようにする
+	var normalized strings.Builder
+	var origins []int // 正規化後の各バイトに対応する元の文字列のバイト位置
+	runes := []rune(text)
+	offset := 0
+	for i := 0; i < len(runes); {
+		j := i + 1
+		for j < len(runes) && isVoicedMark(runes[j]) {
+			j++
+		}
+		segment := string(runes[i:j])
+		n := Normalize(segment)
+		normalized.WriteString(n)
+		for k := 0; k < len(n); k++ {
+			origins = append(origins, offset)
+		}
+		offset += len(segment)
+		i = j
+	}
+	origins = append(origins, len(text))
+
+	haystack := normalized.String()
+	var b strings.Builder
+	found := false
+	last := 0 // 元の文字列で書き出し済みのバイト位置
+	for pos := 0; pos < len(haystack); {
+		idx := strings.Index(haystack[pos:], normalizedQuery)
+		if idx < 0 {
+			break
+		}
+		start := origins[pos+idx]
+		end := origins[pos+idx+len(normalizedQuery)]
+		if start >= last && end > start {
+			b.WriteString(html.EscapeString(text[last:start]))
+			b.WriteString("<mark>")
+			b.WriteString(html.EscapeString(text[start:end]))
+			b.WriteString("</mark>")
+			last = end
+			found = true
+		}
+		pos += idx + len(normalizedQuery)
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+
+	return b.String(), found
+}
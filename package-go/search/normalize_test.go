@@ -0,0 +1,113 @@
+package search
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "half-width katakana", in: "ｼﾞｭｳﾐﾝ", want: "じゅうみん"},
+		{name: "half-width semi-voiced mark", in: "ﾊﾟｽﾎﾟｰﾄ", want: "ぱすぽーと"},
+		{name: "full-width katakana", in: "ジュウミンキホンダイチョウシステム", want: "じゅうみんきほんだいちょうしすてむ"},
+		{name: "hiragana is unchanged", in: "じゅうみん", want: "じゅうみん"},
+		{name: "small and rare katakana", in: "ァヵヶヴ", want: "ぁゕゖゔ"},
+		{name: "full-width alphanumerics", in: "ＡＢＣ１２３", want: "abc123"},
+		{name: "half-width uppercase", in: "GIS System", want: "gis system"},
+		{name: "full-width space", in: "住民　基本", want: "住民 基本"},
+		{name: "kanji is unchanged", in: "住民基本台帳システム", want: "住民基本台帳しすてむ"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		query     string
+		want      string
+		wantFound bool
+	}{
+		{
+			name:      "exact match",
+			text:      "住民基本台帳システム",
+			query:     "基本台帳",
+			want:      "住民<mark>基本台帳</mark>システム",
+			wantFound: true,
+		},
+		{
+			name:      "half-width query against full-width katakana",
+			text:      "ジュウミンキホンダイチョウシステム",
+			query:     "ｼﾞｭｳﾐﾝ",
+			want:      "<mark>ジュウミン</mark>キホンダイチョウシステム",
+			wantFound: true,
+		},
+		{
+			name:      "hiragana query against katakana",
+			text:      "税務管理システム",
+			query:     "しすてむ",
+			want:      "税務管理<mark>システム</mark>",
+			wantFound: true,
+		},
+		{
+			name:      "katakana query against half-width text",
+			text:      "ｼﾞｭｳﾐﾝ台帳",
+			query:     "ジュウミン",
+			want:      "<mark>ｼﾞｭｳﾐﾝ</mark>台帳",
+			wantFound: true,
+		},
+		{
+			name:      "full-width query against ascii",
+			text:      "GIS連携",
+			query:     "ｇｉｓ",
+			want:      "<mark>GIS</mark>連携",
+			wantFound: true,
+		},
+		{
+			name:      "multiple matches",
+			text:      "システム連携システム",
+			query:     "システム",
+			want:      "<mark>システム</mark>連携<mark>システム</mark>",
+			wantFound: true,
+		},
+		{
+			name:      "html is escaped",
+			text:      "<b>住民</b>",
+			query:     "住民",
+			want:      "&lt;b&gt;<mark>住民</mark>&lt;/b&gt;",
+			wantFound: true,
+		},
+		{
+			name:      "no match",
+			text:      "税務管理システム",
+			query:     "じゅうみん",
+			want:      "税務管理システム",
+			wantFound: false,
+		},
+		{
+			name:      "blank query",
+			text:      "a&b",
+			query:     "  ",
+			want:      "a&amp;b",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := Highlight(tt.text, tt.query)
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("Highlight(%q, %q) = %q, %v, want %q, %v", tt.text, tt.query, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
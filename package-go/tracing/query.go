@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var queryTracer = Tracer("sample-micro-service-api/package-go/database")
+
+// QueryHook はsqlcのクエリごとにスパンを作成する（database.QueryHookとして使う）
+// スパン名はクエリ名（GetSystems など）で、pgx.ErrNoRowsはエラーとして扱わない
+func QueryHook(ctx context.Context, name string) (context.Context, func(err error)) {
+	ctx, span := queryTracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", name),
+		),
+	)
+	return ctx, func(err error) {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			RecordError(span, err)
+		}
+		span.End()
+	}
+}
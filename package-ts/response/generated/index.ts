@@ -1,7 +1,54 @@
 import { makeApi, Zodios, type ZodiosOptions } from "@zodios/core";
 import { z } from "zod";
 
-const model_HealthCheck = z.object({ status: z.string() }).passthrough();
+const model_HealthCheck = z
+  .object({
+    status: z.string(),
+    service: z.string().optional(),
+    timestamp: z.string().datetime({ offset: true }).optional(),
+    checks: z
+      .array(
+        z
+          .object({
+            name: z.string(),
+            status: z.string(),
+            latencyMs: z.number(),
+            error: z.string().optional(),
+          })
+          .passthrough()
+      )
+      .optional(),
+  })
+  .passthrough();
+const model_LogLevels = z
+  .object({ level: z.string(), loggers: z.record(z.string()) })
+  .passthrough();
+const UpdateLogLevel_Body = z
+  .object({ logger: z.string().optional(), level: z.string() })
+  .passthrough();
+const model_SearchResult = z
+  .object({
+    query: z.string(),
+    sections: z.array(
+      z
+        .object({
+          type: z.string(),
+          items: z.array(
+            z
+              .object({
+                id: z.string(),
+                title: z.string(),
+                subtitle: z.string().nullish(),
+                highlight: z.string(),
+                score: z.number(),
+              })
+              .passthrough()
+          ),
+        })
+        .passthrough()
+    ),
+  })
+  .passthrough();
 const common_Error = z
   .object({
     type: z.string().url().optional(),
@@ -24,29 +71,193 @@ const model_System = z
   .object({
     id: z.string().uuid(),
     systemName: z.string(),
+    systemNameKana: z.string().nullish(),
     localGovernmentId: z.string().nullish(),
     createdAt: z.string().datetime({ offset: true }),
     updatedAt: z.string().datetime({ offset: true }),
     mailAddress: z.string().email(),
     telephone: z.string().nullish(),
     remark: z.string().nullish(),
+    score: z.number().optional(),
+    highlight: z.string().optional(),
+  })
+  .passthrough();
+
+const model_SystemImportResult = z
+  .object({
+    dryRun: z.boolean(),
+    committed: z.boolean(),
+    total: z.number().int(),
+    creates: z.number().int(),
+    updates: z.number().int(),
+    errors: z.number().int(),
+    rows: z.array(
+      z
+        .object({
+          line: z.number().int(),
+          action: z.string(),
+          systemName: z.string(),
+          id: z.string().uuid().nullish(),
+          localGovernmentId: z.string().nullish(),
+          errors: z.array(
+            z.object({ field: z.string(), message: z.string() }).passthrough()
+          ),
+        })
+        .passthrough()
+    ),
+  })
+  .passthrough();
+const model_Vendor = z
+  .object({
+    corporateNumber: z.string(),
+    vendorName: z.string(),
+    vendorNameKana: z.string().nullish(),
+    prefectureName: z.string().nullish(),
+    cityName: z.string().nullish(),
+    streetNumber: z.string().nullish(),
+    postCode: z.string().nullish(),
+    closeDate: z.string().nullish(),
+    ntaUpdatedAt: z.string().nullish(),
+    projects: z.array(
+      z
+        .object({
+          id: z.string().uuid(),
+          projectName: z.string(),
+          localGovernmentId: z.string(),
+        })
+        .passthrough()
+    ),
+    systems: z.array(
+      z.object({ id: z.string().uuid(), systemName: z.string() }).passthrough()
+    ),
   })
   .passthrough();
 
 export const schemas = {
   model_HealthCheck,
+  model_LogLevels,
+  UpdateLogLevel_Body,
+  model_SearchResult,
   common_Error,
   model_System,
+  model_SystemImportResult,
+  model_Vendor,
 };
 
 const endpoints = makeApi([
+  {
+    method: "get",
+    path: "/admin/log-level",
+    alias: "GetLogLevels",
+    description: `Return the root log level and the loggers with their own level.
+Requires the ADMIN_TOKEN as a bearer token; the endpoint is disabled (404) when ADMIN_TOKEN is not set.
+`,
+    requestFormat: "json",
+    response: model_LogLevels,
+    errors: [
+      {
+        status: 401,
+        description: `Missing or invalid admin token`,
+        schema: common_Error,
+      },
+    ],
+  },
+  {
+    method: "put",
+    path: "/admin/log-level",
+    alias: "UpdateLogLevel",
+    description: `Change the root log level, or the level of a named logger (handler, service, database) when logger is set.
+Setting level to "inherit" for a named logger removes its own level so that it follows the root level again.
+The change is not persisted and applies to this instance only.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: UpdateLogLevel_Body,
+      },
+    ],
+    response: model_LogLevels,
+    errors: [
+      {
+        status: 400,
+        description: `Bad Request`,
+        schema: common_Error,
+      },
+      {
+        status: 401,
+        description: `Missing or invalid admin token`,
+        schema: common_Error,
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/api/v1/search",
+    alias: "Search",
+    description: `Search systems (name, reading, remark), projects (project name, vendor name, corporate number),
+groups and local governments (city name and reading) with a single query.
+Full-width and half-width characters, and katakana and hiragana, are treated as equal.
+Results are returned in typed sections ranked by relevance.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "q",
+        type: "Query",
+        schema: z.string().min(1),
+      },
+      {
+        name: "limit",
+        type: "Query",
+        schema: z.number().int().gte(1).lte(50).optional().default(5),
+      },
+      {
+        name: "If-None-Match",
+        type: "Header",
+        schema: z.string().optional(),
+      },
+    ],
+    response: model_SearchResult,
+    errors: [
+      {
+        status: 400,
+        description: `Bad Request`,
+        schema: common_Error,
+      },
+      {
+        status: 429,
+        description: `Too Many Requests (rate limit exceeded; retry after the number of seconds in Retry-After)`,
+        schema: common_Error,
+      },
+      {
+        status: 500,
+        description: `Internal Server Error`,
+        schema: common_Error,
+      },
+    ],
+  },
   {
     method: "get",
     path: "/api/v1/systems",
     alias: "GetSystems",
-    description: `Retrieve a list of all systems with optional search filters`,
+    description: `Retrieve a list of all systems with optional search filters.
+CSV (UTF-8 with BOM), XLSX and NDJSON are streamed row by row when requested
+via the format parameter or the Accept header.
+`,
     requestFormat: "json",
     parameters: [
+      {
+        name: "q",
+        type: "Query",
+        schema: z.string().optional(),
+      },
+      {
+        name: "limit",
+        type: "Query",
+        schema: z.number().int().gte(1).lte(200).optional().default(50),
+      },
       {
         name: "systemName",
         type: "Query",
@@ -62,9 +273,34 @@ const endpoints = makeApi([
         type: "Query",
         schema: z.string().optional(),
       },
+      {
+        name: "format",
+        type: "Query",
+        schema: z.string().optional(),
+      },
+      {
+        name: "headers",
+        type: "Query",
+        schema: z.string().optional().default("en"),
+      },
+      {
+        name: "If-None-Match",
+        type: "Header",
+        schema: z.string().optional(),
+      },
     ],
     response: z.array(model_System),
     errors: [
+      {
+        status: 400,
+        description: `Bad Request`,
+        schema: common_Error,
+      },
+      {
+        status: 429,
+        description: `Too Many Requests (rate limit exceeded; retry after the number of seconds in Retry-After)`,
+        schema: common_Error,
+      },
       {
         status: 500,
         description: `Internal Server Error`,
@@ -92,6 +328,72 @@ const endpoints = makeApi([
         description: `Bad Request`,
         schema: common_Error,
       },
+      {
+        status: 413,
+        description: `Payload Too Large (request body exceeds HTTP_MAX_BODY_BYTES or the route limit)`,
+        schema: common_Error,
+      },
+      {
+        status: 429,
+        description: `Too Many Requests (rate limit exceeded; retry after the number of seconds in Retry-After)`,
+        schema: common_Error,
+      },
+      {
+        status: 500,
+        description: `Internal Server Error`,
+        schema: common_Error,
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/api/v1/systems/import",
+    alias: "ImportSystems",
+    description: `Import systems from a CSV (UTF-8 or Shift_JIS) or XLSX file.
+Each row is validated and matched against existing systems by systemName.
+When dryRun is true (the default) only the report is returned; otherwise all rows
+are committed in a single transaction, or nothing is written if any row has errors.
+`,
+    requestFormat: "form-data",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: z.object({ file: z.instanceof(File) }).passthrough(),
+      },
+      {
+        name: "dryRun",
+        type: "Query",
+        schema: z.boolean().optional().default(true),
+      },
+      {
+        name: "encoding",
+        type: "Query",
+        schema: z.string().optional().default("auto"),
+      },
+    ],
+    response: model_SystemImportResult,
+    errors: [
+      {
+        status: 400,
+        description: `Bad Request`,
+        schema: common_Error,
+      },
+      {
+        status: 422,
+        description: `Some rows have errors; nothing was committed`,
+        schema: model_SystemImportResult,
+      },
+      {
+        status: 413,
+        description: `Payload Too Large (request body exceeds HTTP_MAX_BODY_BYTES or the route limit)`,
+        schema: common_Error,
+      },
+      {
+        status: 429,
+        description: `Too Many Requests (rate limit exceeded; retry after the number of seconds in Retry-After)`,
+        schema: common_Error,
+      },
       {
         status: 500,
         description: `Internal Server Error`,
@@ -119,6 +421,11 @@ const endpoints = makeApi([
         description: `System not found`,
         schema: common_Error,
       },
+      {
+        status: 429,
+        description: `Too Many Requests (rate limit exceeded; retry after the number of seconds in Retry-After)`,
+        schema: common_Error,
+      },
       {
         status: 500,
         description: `Internal Server Error`,
@@ -156,6 +463,16 @@ const endpoints = makeApi([
         description: `System not found`,
         schema: common_Error,
       },
+      {
+        status: 413,
+        description: `Payload Too Large (request body exceeds HTTP_MAX_BODY_BYTES or the route limit)`,
+        schema: common_Error,
+      },
+      {
+        status: 429,
+        description: `Too Many Requests (rate limit exceeded; retry after the number of seconds in Retry-After)`,
+        schema: common_Error,
+      },
       {
         status: 500,
         description: `Internal Server Error`,
@@ -183,6 +500,11 @@ const endpoints = makeApi([
         description: `System not found`,
         schema: common_Error,
       },
+      {
+        status: 429,
+        description: `Too Many Requests (rate limit exceeded; retry after the number of seconds in Retry-After)`,
+        schema: common_Error,
+      },
       {
         status: 500,
         description: `Internal Server Error`,
@@ -192,19 +514,95 @@ const endpoints = makeApi([
   },
   {
     method: "get",
-    path: "/health",
-    alias: "HealthCheck",
-    description: `Get the status of the server.`,
+    path: "/api/v1/vendors",
+    alias: "GetVendors",
+    description: `List vendors by corporate number with the projects and systems they are involved in.
+Vendors are collected from projects and system basic information, and enriched with
+the National Tax Agency corporate number data when it has been imported.
+`,
     requestFormat: "json",
-    response: z.object({ status: z.string() }).passthrough(),
+    parameters: [
+      {
+        name: "corporateNumber",
+        type: "Query",
+        schema: z.string().optional(),
+      },
+      {
+        name: "If-None-Match",
+        type: "Header",
+        schema: z.string().optional(),
+      },
+    ],
+    response: z.array(model_Vendor),
     errors: [
+      {
+        status: 400,
+        description: `Bad Request`,
+        schema: common_Error,
+      },
+      {
+        status: 429,
+        description: `Too Many Requests (rate limit exceeded; retry after the number of seconds in Retry-After)`,
+        schema: common_Error,
+      },
       {
         status: 500,
-        description: `Server error`,
+        description: `Internal Server Error`,
         schema: common_Error,
       },
     ],
   },
+  {
+    method: "get",
+    path: "/health",
+    alias: "HealthCheck",
+    description: `Alias of /readyz, kept for existing health checks.`,
+    requestFormat: "json",
+    response: model_HealthCheck,
+    errors: [
+      {
+        status: 503,
+        description: `A dependency check failed, or the server is shutting down and draining in-flight requests (status is "draining")`,
+        schema: model_HealthCheck,
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/livez",
+    alias: "Liveness",
+    description: `Returns 200 while the process is able to serve requests. Dependencies are not checked.`,
+    requestFormat: "json",
+    response: model_HealthCheck,
+  },
+  {
+    method: "get",
+    path: "/metrics",
+    alias: "Metrics",
+    description: `Metrics in the Prometheus text exposition format: HTTP request counts, latency histograms
+and in-flight requests labelled by route template (e.g. /api/v1/systems/:id) and status,
+database connection pool statistics, and per-query duration histograms for sqlc queries.
+`,
+    requestFormat: "json",
+    response: z.string(),
+  },
+  {
+    method: "get",
+    path: "/readyz",
+    alias: "Readiness",
+    description: `Run the registered dependency checks (database ping, migration version and connection pool saturation)
+and report each result with its latency. Returns 503 when any check fails or while the server is draining.
+`,
+    requestFormat: "json",
+    response: model_HealthCheck,
+    errors: [
+      {
+        status: 503,
+        description: `A dependency check failed, or the server is shutting down and draining in-flight requests (status is "draining")`,
+        schema: model_HealthCheck,
+      },
+    ],
+  },
 ]);
 
 export const api = new Zodios(endpoints);